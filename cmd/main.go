@@ -2,61 +2,368 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aakosarev/banner-rotation/internal/config"
+	"github.com/aakosarev/banner-rotation/internal/event"
+	"github.com/aakosarev/banner-rotation/internal/grpchandler"
 	"github.com/aakosarev/banner-rotation/internal/handler"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/metrics"
+	"github.com/aakosarev/banner-rotation/internal/outbox"
+	"github.com/aakosarev/banner-rotation/internal/routing"
 	"github.com/aakosarev/banner-rotation/internal/service"
 	"github.com/aakosarev/banner-rotation/internal/storage"
+	"github.com/aakosarev/banner-rotation/internal/storage/memory"
+	redisstorage "github.com/aakosarev/banner-rotation/internal/storage/redis"
+	"github.com/aakosarev/banner-rotation/internal/tracing"
 	"github.com/aakosarev/banner-rotation/pkg/client/postgresql"
+	"github.com/aakosarev/banner-rotation/pkg/client/redis"
+	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 )
 
 func main() {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// ctx is done on SIGINT or SIGTERM, which starts the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	cfg := config.GetConfig()
 
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// the log package, which the clients in pkg use, writes through the logger too
+	slog.SetDefault(logger)
+
 	router := httprouter.New()
 
-	pgConfig := postgresql.NewPgConfig(
-		cfg.PostgreSQL.Username, cfg.PostgreSQL.Password,
-		cfg.PostgreSQL.Host, cfg.PostgreSQL.Port, cfg.PostgreSQL.Database,
-	)
+	tracerProvider, err := tracing.NewProvider(ctx, cfg.Tracing.Exporter, cfg.Tracing.Endpoint)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	strategies, err := newStrategies(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	pgClient, err := postgresql.NewClient(ctx, 5, time.Second*5, pgConfig)
+	publisher, err := newPublisher(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	rotationStorage := storage.NewStorage(pgClient)
-	rotationService := service.NewService(rotationStorage)
-	rotationHandler := handler.NewHandler(rotationService)
+	var (
+		rotationService *service.Service
+		relay           *outbox.Relay
+		closeStorage    func()
+	)
 
-	rotationHandler.Register(router)
+	switch cfg.Storage {
+	case "postgresql":
+		pgConfig := postgresql.NewPgConfig(
+			cfg.PostgreSQL.Username, cfg.PostgreSQL.Password,
+			cfg.PostgreSQL.Host, cfg.PostgreSQL.Port, cfg.PostgreSQL.Database,
+		)
 
-	start(router, cfg)
-}
+		pgClient, err := postgresql.NewClient(ctx, 5, time.Second*5, pgConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		closeStorage = pgClient.Close
 
-func start(router http.Handler, cfg *config.Config) {
-	var server *http.Server
+		rotationStorage := storage.NewStorage(pgClient, logger)
+		rotationService = service.NewService(rotationStorage, strategies, logger)
+		relay, err = outbox.NewRelay(rotationStorage, publisher, cfg.Outbox.Interval, cfg.Outbox.BatchSize, cfg.Outbox.Retention, logger)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "redis":
+		redisClient, err := redis.NewClient(ctx, cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		closeStorage = func() { redisClient.Close() }
 
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.HTTP.IP, cfg.HTTP.Port))
+		rotationStorage := redisstorage.NewStorage(redisClient, cfg.Redis.BucketRetention)
+		rotationService = service.NewService(rotationStorage, strategies, logger)
+		relay, err = outbox.NewRelay(rotationStorage, publisher, cfg.Outbox.Interval, cfg.Outbox.BatchSize, cfg.Outbox.Retention, logger)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "memory":
+		closeStorage = func() {}
+
+		rotationStorage := memory.NewStorage()
+		rotationService = service.NewService(rotationStorage, strategies, logger)
+		relay, err = outbox.NewRelay(rotationStorage, publisher, cfg.Outbox.Interval, cfg.Outbox.BatchSize, cfg.Outbox.Retention, logger)
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown storage %q", cfg.Storage)
+	}
+
+	// the workers outlive ctx, so they keep flushing and relaying while the servers drain
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	workers := sync.WaitGroup{}
+
+	if cfg.Cache.Enabled {
+		rotationService.EnableStatsCache(cfg.Cache.TTL)
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := rotationService.RunStatsFlush(workersCtx, cfg.Cache.FlushInterval); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	attribution, err := newAttribution(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
+	rotationService.SetAttribution(attribution)
+
+	if cfg.Attribution.Retention > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := rotationService.RunImpressionPruning(workersCtx, cfg.Attribution.PruneInterval, cfg.Attribution.Retention); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relay.Run(workersCtx)
+	}()
+
+	instrumentedRouter := routing.NewRouter(router, metrics.Middleware, tracing.Middleware, logging.Middleware(logger))
+
+	rotationHandler := handler.NewHandler(rotationService, logger)
+
+	rotationHandler.Register(instrumentedRouter)
+
+	health := handler.NewHealth(rotationService, logger)
+	health.Register(router)
 
-	server = &http.Server{
-		Handler:      router,
+	router.Handler(http.MethodGet, "/metrics", metrics.Handler())
+
+	grpcServer := grpc.NewServer()
+	grpchandler.NewHandler(rotationService, logger).Register(grpcServer)
+
+	httpServer := &http.Server{
+		Handler:      logging.RequestIDMiddleware(router),
 		WriteTimeout: 30 * time.Second,
 		ReadTimeout:  30 * time.Second,
 	}
 
-	if err = server.Serve(listener); err != nil {
-		log.Fatal(err)
+	serveErrors := make(chan error, 2)
+	go func() {
+		serveErrors <- start(httpServer, cfg)
+	}()
+	go func() {
+		serveErrors <- startGRPC(grpcServer, cfg)
+	}()
+
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case err = <-serveErrors:
+		slog.Error("server failed, shutting down", slog.Any("error", err))
+	}
+	stop()
+
+	// load balancers stop routing to the service once readiness fails, before the servers stop listening
+	health.SetDraining()
+	time.Sleep(cfg.Shutdown.ReadinessDelay)
+
+	shutdown(cfg.Shutdown.Timeout, httpServer, grpcServer, func() {
+		stopWorkers()
+		workers.Wait()
+	}, relay, publisher, closeStorage)
+
+	// the spans of the teardown are exported too
+	tracingCtx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
+	defer cancel()
+
+	if err = tracerProvider.Shutdown(tracingCtx); err != nil {
+		slog.Error("failed to export spans on shutdown", slog.Any("error", err))
+	}
+}
+
+// shutdown drains the servers within timeout and then tears down what they used, in order: the workers
+// flush the cached statistics, the relay publishes the events of the last flush, the publisher
+// and the storage are closed.
+func shutdown(timeout time.Duration, httpServer *http.Server, grpcServer *grpc.Server, stopWorkers func(),
+	relay *outbox.Relay, publisher event.Publisher, closeStorage func()) {
+	drainCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(drained)
+	}()
+
+	if err := httpServer.Shutdown(drainCtx); err != nil {
+		slog.Error("failed to drain http requests", slog.Any("error", err))
+	}
+
+	select {
+	case <-drained:
+	case <-drainCtx.Done():
+		slog.Error("failed to drain grpc requests in time")
+		grpcServer.Stop()
+	}
+
+	stopWorkers()
+
+	relayCtx, cancelRelay := context.WithTimeout(context.Background(), timeout)
+	defer cancelRelay()
+
+	if err := relay.RelayAll(relayCtx); err != nil {
+		slog.Error("failed to relay outbox events on shutdown", slog.Any("error", err))
+	}
+
+	if err := publisher.Close(); err != nil {
+		slog.Error("failed to close event publisher", slog.Any("error", err))
+	}
+
+	closeStorage()
+}
+
+func newStrategies(cfg *config.Config) (*mab.Strategies, error) {
+	opts := mab.Options{
+		Epsilon:      cfg.MAB.Epsilon,
+		EpsilonDecay: cfg.MAB.EpsilonDecay,
+		Discount:     cfg.MAB.Discount,
+		Window:       cfg.MAB.Window,
+
+		LinUCBAlpha:     cfg.MAB.LinUCBAlpha,
+		LinUCBDimension: cfg.MAB.LinUCBDimension,
+
+		Prior: mab.Prior{
+			Kind:  cfg.MAB.Prior,
+			Shows: cfg.MAB.PriorShows,
+			CTR:   cfg.MAB.PriorCTR,
+		},
+		Budget: mab.Budget{
+			Shows: cfg.MAB.BudgetShows,
+			Share: cfg.MAB.BudgetShare,
+		},
+
+		Reward:       cfg.MAB.Reward,
+		RevenueScale: cfg.MAB.RevenueScale,
+	}
+
+	fallback, err := mab.New(cfg.MAB.Strategy, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := mab.NewStrategies(fallback)
+
+	// a slot with its own reward needs its own strategy, even if it uses the default one
+	slots := make(map[string]struct{}, len(cfg.MAB.Slots)+len(cfg.MAB.SlotRewards))
+	for slot := range cfg.MAB.Slots {
+		slots[slot] = struct{}{}
+	}
+	for slot := range cfg.MAB.SlotRewards {
+		slots[slot] = struct{}{}
+	}
+
+	for slot := range slots {
+		slotID, err := uuid.Parse(slot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot id %q: %w", slot, err)
+		}
+
+		name, ok := cfg.MAB.Slots[slot]
+		if !ok {
+			name = cfg.MAB.Strategy
+		}
+
+		slotOpts := opts
+		if reward, ok := cfg.MAB.SlotRewards[slot]; ok {
+			slotOpts.Reward = reward
+		}
+
+		strategy, err := mab.New(name, slotOpts)
+		if err != nil {
+			return nil, fmt.Errorf("slot %s: %w", slot, err)
+		}
+
+		strategies.SetForSlot(slotID, strategy)
+	}
+
+	return strategies, nil
+}
+
+func newAttribution(cfg *config.Config) (service.Attribution, error) {
+	attribution := service.Attribution{
+		Window:            cfg.Attribution.Window,
+		SlotWindows:       make(map[uuid.UUID]time.Duration, len(cfg.Attribution.SlotWindows)),
+		RequireImpression: cfg.Attribution.RequireImpression,
+	}
+
+	for slot, window := range cfg.Attribution.SlotWindows {
+		slotID, err := uuid.Parse(slot)
+		if err != nil {
+			return service.Attribution{}, fmt.Errorf("invalid slot id %q: %w", slot, err)
+		}
+		attribution.SlotWindows[slotID] = window
+	}
+
+	return attribution, nil
+}
+
+func newPublisher(cfg *config.Config) (event.Publisher, error) {
+	switch cfg.Events.Publisher {
+	case "kafka":
+		return event.NewKafkaPublisher(cfg.Events.Brokers, cfg.Events.Topic), nil
+	case "file":
+		return event.NewFilePublisher(cfg.Events.Path)
+	case "memory":
+		return event.NewMemoryPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", cfg.Events.Publisher)
+	}
+}
+
+func startGRPC(server *grpc.Server, cfg *config.Config) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.GRPC.IP, cfg.GRPC.Port))
+	if err != nil {
+		return err
+	}
+
+	return server.Serve(listener)
+}
+
+func start(server *http.Server, cfg *config.Config) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.HTTP.IP, cfg.HTTP.Port))
+	if err != nil {
+		return err
+	}
+
+	if err = server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
 	"sync"
+	"time"
 )
 
 type Config struct {
@@ -11,6 +12,24 @@ type Config struct {
 		IP   string `yaml:"ip"`
 		Port string `yaml:"port"`
 	} `yaml:"http"`
+	GRPC struct {
+		IP   string `yaml:"ip"`
+		Port string `yaml:"port"`
+	} `yaml:"grpc"`
+	Log struct {
+		// Level is debug, info, warn or error. Selections are logged with their statistics at debug.
+		Level string `yaml:"level" env-default:"info"`
+		// Format is json or text.
+		Format string `yaml:"format" env-default:"json"`
+	} `yaml:"log"`
+	Shutdown struct {
+		// Timeout is how long the servers drain in-flight requests on SIGINT or SIGTERM.
+		Timeout time.Duration `yaml:"timeout" env-default:"15s"`
+		// ReadinessDelay is how long /readyz reports draining before the servers stop accepting requests.
+		ReadinessDelay time.Duration `yaml:"readiness_delay"`
+	} `yaml:"shutdown"`
+	// Storage is postgresql, redis or memory.
+	Storage    string `yaml:"storage" env-default:"postgresql"`
 	PostgreSQL struct {
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
@@ -18,6 +37,73 @@ type Config struct {
 		Host     string `yaml:"host"`
 		Port     string `yaml:"port"`
 	} `yaml:"postgresql"`
+	Redis struct {
+		Addr     string `yaml:"addr"`
+		Password string `yaml:"password"`
+		DB       int    `yaml:"db"`
+		// The hourly statistics are kept for BucketRetention, which should cover the window of the strategy,
+		// zero for ever.
+		BucketRetention time.Duration `yaml:"bucket_retention" env-default:"720h"`
+	} `yaml:"redis"`
+	MAB struct {
+		Strategy        string            `yaml:"strategy"`
+		Epsilon         float64           `yaml:"epsilon"`
+		EpsilonDecay    float64           `yaml:"epsilon_decay"`
+		Discount        float64           `yaml:"discount"`
+		Window          time.Duration     `yaml:"window"`
+		LinUCBAlpha     float64           `yaml:"linucb_alpha"`
+		LinUCBDimension int               `yaml:"linucb_dimension"`
+		Slots           map[string]string `yaml:"slots"`
+
+		// Prior is optimistic, slot-average or none. New banners are credited with PriorShows shows at
+		// PriorCTR, or at the average click-through rate of the slot. PriorShows must be at least 1/PriorCTR.
+		Prior      string  `yaml:"prior" env-default:"none"`
+		PriorShows int     `yaml:"prior_shows"`
+		PriorCTR   float64 `yaml:"prior_ctr"`
+		// BudgetShare of the selections go to the banners with fewer than BudgetShows shows.
+		BudgetShows int     `yaml:"budget_shows"`
+		BudgetShare float64 `yaml:"budget_share"`
+
+		// Reward is click, conversion or revenue, and SlotRewards overrides it for single slots.
+		// Revenue is scaled down by RevenueScale, the revenue worth one click. Set it to the value of the
+		// most valuable conversion, so that a show earns at most one click.
+		Reward       string            `yaml:"reward" env-default:"click"`
+		RevenueScale float64           `yaml:"revenue_scale"`
+		SlotRewards  map[string]string `yaml:"slot_rewards"`
+	} `yaml:"mab"`
+	Events struct {
+		Publisher string   `yaml:"publisher"`
+		Path      string   `yaml:"path"`
+		Brokers   []string `yaml:"brokers"`
+		Topic     string   `yaml:"topic"`
+	} `yaml:"events"`
+	Outbox struct {
+		Interval  time.Duration `yaml:"interval" env-default:"1s"`
+		BatchSize int           `yaml:"batch_size" env-default:"100"`
+		// Sent events are deleted once they are older than Retention, zero keeps them for ever.
+		Retention time.Duration `yaml:"retention" env-default:"24h"`
+	} `yaml:"outbox"`
+	Tracing struct {
+		// Exporter is stdout, otlp or none.
+		Exporter string `yaml:"exporter" env-default:"none"`
+		// Endpoint is the host:port of the OTLP gRPC collector.
+		Endpoint string `yaml:"endpoint"`
+	} `yaml:"tracing"`
+	Cache struct {
+		Enabled       bool          `yaml:"enabled"`
+		TTL           time.Duration `yaml:"ttl" env-default:"1m"`
+		FlushInterval time.Duration `yaml:"flush_interval" env-default:"1s"`
+	} `yaml:"cache"`
+	Attribution struct {
+		// Window is how long after a show its clicks and conversions count, zero for no limit.
+		// SlotWindows overrides it for single slots.
+		Window            time.Duration            `yaml:"window"`
+		SlotWindows       map[string]time.Duration `yaml:"slot_windows"`
+		RequireImpression bool                     `yaml:"require_impression"`
+		// Impressions are kept for Retention, which should be longer than every window, zero for ever.
+		Retention     time.Duration `yaml:"retention"`
+		PruneInterval time.Duration `yaml:"prune_interval" env-default:"1h"`
+	} `yaml:"attribution"`
 }
 
 var instance *Config
@@ -8,4 +8,15 @@ var (
 	ErrBannerNotFound            = errors.New("banner not found")
 	ErrSlotNotFound              = errors.New("slot not found")
 	ErrSocialGroupNotFound       = errors.New("social group not found")
+	ErrBannerLinkedToSlot        = errors.New("banner is linked to a slot")
+	ErrSlotHasBanners            = errors.New("slot has linked banners")
+	ErrUnknownStatDimension      = errors.New("unknown statistics dimension")
+	ErrBannerNotLinkedToSlot     = errors.New("banner is not linked to this slot")
+	ErrTooManyBannerSlots        = errors.New("too many banner and slot pairs")
+	ErrNegativeConversionValue   = errors.New("conversion value is negative")
+	ErrImpressionNotFound        = errors.New("impression not found")
+	ErrImpressionExpired         = errors.New("impression is outside the attribution window")
+	ErrImpressionRequired        = errors.New("impression id is required")
+	ErrRepeatedStatDimension     = errors.New("statistics dimension is repeated")
+	ErrImpressionAlreadyRewarded = errors.New("impression already has this click or conversion")
 )
@@ -0,0 +1,47 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	publisher, err := NewFilePublisher(path)
+	require.NoError(t, err)
+
+	stat := &model.Stat{BannerID: uuid.New(), SlotID: uuid.New(), GroupID: uuid.New()}
+	show := model.NewEvent(model.EventShow, stat)
+	click := model.NewEvent(model.EventClick, stat)
+
+	require.NoError(t, publisher.Publish(context.Background(), show))
+	require.NoError(t, publisher.Publish(context.Background(), click))
+	require.NoError(t, publisher.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var events []*model.Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		event := &model.Event{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), event))
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 2)
+	require.Equal(t, show.ID, events[0].ID)
+	require.Equal(t, model.EventShow, events[0].Type)
+	require.Equal(t, model.EventClick, events[1].Type)
+	require.Equal(t, stat.BannerID, events[1].BannerID)
+	require.True(t, show.Timestamp.Equal(events[0].Timestamp))
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/segmentio/kafka-go"
+	"time"
+)
+
+// KafkaPublisher writes events to a Kafka topic, keyed by slot so the events of a slot stay ordered.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, events ...*model.Event) error {
+	messages := make([]kafka.Message, 0, len(events))
+
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, kafka.Message{
+			Key:   []byte(event.SlotID.String()),
+			Value: value,
+			Time:  event.Timestamp,
+		})
+	}
+
+	return p.writer.WriteMessages(ctx, messages...)
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
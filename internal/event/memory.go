@@ -0,0 +1,37 @@
+package event
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"sync"
+)
+
+// MemoryPublisher keeps published events in memory.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []*model.Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, events ...*model.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events returns the events published so far.
+func (p *MemoryPublisher) Events() []*model.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]*model.Event(nil), p.events...)
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,12 @@
+// Package event delivers show and click events to analytics.
+package event
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, events ...*model.Event) error
+	Close() error
+}
@@ -0,0 +1,39 @@
+package grpchandler
+
+import (
+	"context"
+	stdErrors "errors"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+var errorCodes = map[error]codes.Code{
+	errors.ErrBannerNotFound:          codes.NotFound,
+	errors.ErrSlotNotFound:            codes.NotFound,
+	errors.ErrSocialGroupNotFound:     codes.NotFound,
+	errors.ErrNoOneBannerFoundForSlot: codes.NotFound,
+	errors.ErrImpressionNotFound:      codes.NotFound,
+
+	errors.ErrBannerAlreadyLinkedToSlot: codes.AlreadyExists,
+	errors.ErrImpressionAlreadyRewarded: codes.AlreadyExists,
+
+	errors.ErrNegativeConversionValue: codes.InvalidArgument,
+	errors.ErrImpressionRequired:      codes.InvalidArgument,
+
+	errors.ErrImpressionExpired: codes.FailedPrecondition,
+}
+
+// toStatus turns a domain error into a status with the matching code. Unknown errors are internal ones,
+// their messages are logged rather than sent to the client.
+func (h *Handler) toStatus(ctx context.Context, err error) error {
+	for domainErr, code := range errorCodes {
+		if stdErrors.Is(err, domainErr) {
+			return status.Error(code, domainErr.Error())
+		}
+	}
+
+	h.logger.ErrorContext(ctx, "internal error", slog.Any("error", err))
+	return status.Error(codes.Internal, "internal server error")
+}
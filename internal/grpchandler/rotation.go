@@ -0,0 +1,188 @@
+// Package grpchandler serves the rotation service over gRPC, next to the HTTP handler.
+package grpchandler
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/pkg/api/rotation"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+type service interface {
+	AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
+	RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
+	SelectBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID, features model.Features) (*model.Selection, error)
+	AddClick(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) error
+	AddConversion(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID, value float64) error
+	AddImpressionClick(ctx context.Context, impressionID *uuid.UUID) error
+	AddImpressionConversion(ctx context.Context, impressionID *uuid.UUID, value float64) error
+}
+
+type Handler struct {
+	rotation.UnimplementedRotationServiceServer
+
+	service service
+	logger  *slog.Logger
+}
+
+func NewHandler(service service, logger *slog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) Register(server *grpc.Server) {
+	rotation.RegisterRotationServiceServer(server, h)
+}
+
+func (h *Handler) AddBannerToSlot(ctx context.Context, request *rotation.AddBannerToSlotRequest) (*rotation.AddBannerToSlotResponse, error) {
+	bannerID, err := parseID("banner_id", request.GetBannerId())
+	if err != nil {
+		return nil, err
+	}
+
+	slotID, err := parseID("slot_id", request.GetSlotId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.AddBannerToSlot(ctx, &bannerID, &slotID)
+	if err != nil {
+		return nil, h.toStatus(ctx, err)
+	}
+
+	return &rotation.AddBannerToSlotResponse{}, nil
+}
+
+func (h *Handler) RemoveBannerFromSlot(ctx context.Context, request *rotation.RemoveBannerFromSlotRequest) (*rotation.RemoveBannerFromSlotResponse, error) {
+	bannerID, err := parseID("banner_id", request.GetBannerId())
+	if err != nil {
+		return nil, err
+	}
+
+	slotID, err := parseID("slot_id", request.GetSlotId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.RemoveBannerFromSlot(ctx, &bannerID, &slotID)
+	if err != nil {
+		return nil, h.toStatus(ctx, err)
+	}
+
+	return &rotation.RemoveBannerFromSlotResponse{}, nil
+}
+
+func (h *Handler) SelectBanner(ctx context.Context, request *rotation.SelectBannerRequest) (*rotation.SelectBannerResponse, error) {
+	slotID, err := parseID("slot_id", request.GetSlotId())
+	if err != nil {
+		return nil, err
+	}
+
+	socialGroupID, err := parseID("group_id", request.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := h.service.SelectBanner(ctx, &slotID, &socialGroupID, request.GetFeatures())
+	if err != nil {
+		return nil, h.toStatus(ctx, err)
+	}
+
+	return &rotation.SelectBannerResponse{
+		Banner: &rotation.Banner{
+			Id:          selection.Banner.ID.String(),
+			Description: selection.Banner.Description,
+		},
+		ImpressionId: selection.Impression.ID.String(),
+	}, nil
+}
+
+func (h *Handler) AddClick(ctx context.Context, request *rotation.AddClickRequest) (*rotation.AddClickResponse, error) {
+	if request.GetImpressionId() != "" {
+		impressionID, err := parseID("impression_id", request.GetImpressionId())
+		if err != nil {
+			return nil, err
+		}
+
+		err = h.service.AddImpressionClick(ctx, &impressionID)
+		if err != nil {
+			return nil, h.toStatus(ctx, err)
+		}
+
+		return &rotation.AddClickResponse{}, nil
+	}
+
+	bannerID, err := parseID("banner_id", request.GetBannerId())
+	if err != nil {
+		return nil, err
+	}
+
+	slotID, err := parseID("slot_id", request.GetSlotId())
+	if err != nil {
+		return nil, err
+	}
+
+	socialGroupID, err := parseID("group_id", request.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.AddClick(ctx, &bannerID, &slotID, &socialGroupID)
+	if err != nil {
+		return nil, h.toStatus(ctx, err)
+	}
+
+	return &rotation.AddClickResponse{}, nil
+}
+
+func (h *Handler) AddConversion(ctx context.Context, request *rotation.AddConversionRequest) (*rotation.AddConversionResponse, error) {
+	if request.GetImpressionId() != "" {
+		impressionID, err := parseID("impression_id", request.GetImpressionId())
+		if err != nil {
+			return nil, err
+		}
+
+		err = h.service.AddImpressionConversion(ctx, &impressionID, request.GetValue())
+		if err != nil {
+			return nil, h.toStatus(ctx, err)
+		}
+
+		return &rotation.AddConversionResponse{}, nil
+	}
+
+	bannerID, err := parseID("banner_id", request.GetBannerId())
+	if err != nil {
+		return nil, err
+	}
+
+	slotID, err := parseID("slot_id", request.GetSlotId())
+	if err != nil {
+		return nil, err
+	}
+
+	socialGroupID, err := parseID("group_id", request.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.AddConversion(ctx, &bannerID, &slotID, &socialGroupID, request.GetValue())
+	if err != nil {
+		return nil, h.toStatus(ctx, err)
+	}
+
+	return &rotation.AddConversionResponse{}, nil
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s %q", field, value)
+	}
+	return id, nil
+}
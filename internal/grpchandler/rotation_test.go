@@ -0,0 +1,102 @@
+package grpchandler_test
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/grpchandler"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/service"
+	"github.com/aakosarev/banner-rotation/internal/storage/memory"
+	"github.com/aakosarev/banner-rotation/pkg/api/rotation"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	rotationService := service.NewService(memory.NewStorage(), mab.NewStrategies(mab.StrategyFunc(mab.UCB1)), logging.Discard())
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	grpchandler.NewHandler(rotationService, logging.Discard()).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	client := rotation.NewRotationServiceClient(conn)
+
+	slot := &model.Slot{Description: "slot"}
+	socialGroup := &model.Group{Description: "social group"}
+	banner := &model.Banner{Description: "banner"}
+	require.NoError(t, rotationService.CreateSlot(ctx, slot))
+	require.NoError(t, rotationService.CreateSocialGroup(ctx, socialGroup))
+	require.NoError(t, rotationService.CreateBanner(ctx, banner))
+
+	_, err = client.SelectBanner(ctx, &rotation.SelectBannerRequest{SlotId: "slot", GroupId: socialGroup.ID.String()})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.SelectBanner(ctx, &rotation.SelectBannerRequest{SlotId: slot.ID.String(), GroupId: socialGroup.ID.String()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.AddBannerToSlot(ctx, &rotation.AddBannerToSlotRequest{BannerId: banner.ID.String(), SlotId: slot.ID.String()})
+	require.NoError(t, err)
+
+	_, err = client.AddBannerToSlot(ctx, &rotation.AddBannerToSlotRequest{BannerId: banner.ID.String(), SlotId: slot.ID.String()})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	response, err := client.SelectBanner(ctx, &rotation.SelectBannerRequest{
+		SlotId:   slot.ID.String(),
+		GroupId:  socialGroup.ID.String(),
+		Features: map[string]string{"device": "mobile"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, banner.ID.String(), response.GetBanner().GetId())
+	require.Equal(t, banner.Description, response.GetBanner().GetDescription())
+
+	_, err = client.AddClick(ctx, &rotation.AddClickRequest{
+		BannerId: banner.ID.String(),
+		SlotId:   slot.ID.String(),
+		GroupId:  socialGroup.ID.String(),
+	})
+	require.NoError(t, err)
+
+	_, err = client.AddClick(ctx, &rotation.AddClickRequest{ImpressionId: response.GetImpressionId()})
+	require.NoError(t, err)
+
+	_, err = client.AddClick(ctx, &rotation.AddClickRequest{ImpressionId: banner.ID.String()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.AddConversion(ctx, &rotation.AddConversionRequest{
+		BannerId: banner.ID.String(),
+		SlotId:   slot.ID.String(),
+		GroupId:  socialGroup.ID.String(),
+		Value:    -1,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.AddConversion(ctx, &rotation.AddConversionRequest{
+		BannerId: banner.ID.String(),
+		SlotId:   slot.ID.String(),
+		GroupId:  socialGroup.ID.String(),
+		Value:    9.99,
+	})
+	require.NoError(t, err)
+
+	_, err = client.RemoveBannerFromSlot(ctx, &rotation.RemoveBannerFromSlotRequest{BannerId: banner.ID.String(), SlotId: slot.ID.String()})
+	require.NoError(t, err)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+func (h *Handler) CreateBanner(w http.ResponseWriter, r *http.Request) {
+	banner := model.Banner{}
+	err := decodeBody(r, &banner)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.CreateBanner(r.Context(), &banner)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, banner)
+}
+
+func (h *Handler) GetBanner(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	bannerID, err := pathID(params, "banner_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	banner, err := h.service.GetBanner(r.Context(), &bannerID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, banner)
+}
+
+func (h *Handler) ListBanners(w http.ResponseWriter, r *http.Request) {
+	banners, err := h.service.ListBanners(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if banners == nil {
+		banners = []*model.Banner{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, banners)
+}
+
+func (h *Handler) UpdateBanner(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	bannerID, err := pathID(params, "banner_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	banner := model.Banner{}
+	err = decodeBody(r, &banner)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	banner.ID = bannerID
+
+	err = h.service.UpdateBanner(r.Context(), &banner)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, banner)
+}
+
+func (h *Handler) DeleteBanner(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	bannerID, err := pathID(params, "banner_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.DeleteBanner(r.Context(), &bannerID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeSuccess(w)
+}
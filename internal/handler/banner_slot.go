@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"net/http"
+)
+
+// AddBannersToSlots serves POST /banner-slots with a JSON array of {"banner_id", "slot_id"} pairs.
+func (h *Handler) AddBannersToSlots(w http.ResponseWriter, r *http.Request) {
+	h.changeBannerSlots(w, r, h.service.AddBannersToSlots)
+}
+
+// RemoveBannersFromSlots serves DELETE /banner-slots with a JSON array of {"banner_id", "slot_id"} pairs.
+func (h *Handler) RemoveBannersFromSlots(w http.ResponseWriter, r *http.Request) {
+	h.changeBannerSlots(w, r, h.service.RemoveBannersFromSlots)
+}
+
+func (h *Handler) changeBannerSlots(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, bannerSlots []*model.BannerSlot) ([]*model.BannerSlotResult, error)) {
+	var bannerSlots []*model.BannerSlot
+	err := decodeBody(r, &bannerSlots)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	for _, bannerSlot := range bannerSlots {
+		if bannerSlot == nil {
+			h.writeError(w, r, errInvalidRequest)
+			return
+		}
+	}
+
+	results, err := change(r.Context(), bannerSlots)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			_, response := h.mapError(r.Context(), result.Err)
+			result.Code, result.Error = response.Code, response.Message
+		}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, results)
+}
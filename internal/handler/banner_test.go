@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeService keeps banners and slots in maps. The methods the tests do not need panic.
+type fakeService struct {
+	service
+	banners map[uuid.UUID]*model.Banner
+	slots   map[uuid.UUID]*model.Slot
+	// linked banners and slots cannot be deleted
+	linked map[uuid.UUID]bool
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		banners: make(map[uuid.UUID]*model.Banner),
+		slots:   make(map[uuid.UUID]*model.Slot),
+		linked:  make(map[uuid.UUID]bool),
+	}
+}
+
+func (s *fakeService) CreateBanner(ctx context.Context, banner *model.Banner) error {
+	banner.ID = uuid.New()
+	s.banners[banner.ID] = banner
+	return nil
+}
+
+func (s *fakeService) GetBanner(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error) {
+	banner, ok := s.banners[*bannerID]
+	if !ok {
+		return nil, errors.ErrBannerNotFound
+	}
+	return banner, nil
+}
+
+func (s *fakeService) ListBanners(ctx context.Context) ([]*model.Banner, error) {
+	var banners []*model.Banner
+	for _, banner := range s.banners {
+		banners = append(banners, banner)
+	}
+	return banners, nil
+}
+
+func (s *fakeService) UpdateBanner(ctx context.Context, banner *model.Banner) error {
+	if _, ok := s.banners[banner.ID]; !ok {
+		return errors.ErrBannerNotFound
+	}
+	s.banners[banner.ID] = banner
+	return nil
+}
+
+func (s *fakeService) DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error {
+	if _, ok := s.banners[*bannerID]; !ok {
+		return errors.ErrBannerNotFound
+	}
+	if s.linked[*bannerID] {
+		return errors.ErrBannerLinkedToSlot
+	}
+	delete(s.banners, *bannerID)
+	return nil
+}
+
+// serve sends the request through the routes of the handler and returns the status and body.
+func serve(t *testing.T, h *Handler, method, path, body string) (int, string) {
+	t.Helper()
+
+	router := httprouter.New()
+	h.Register(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestBanners(t *testing.T) {
+	s := newFakeService()
+	h := NewHandler(s, logging.Discard())
+
+	code, body := serve(t, h, http.MethodGet, "/banners", "")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `[]`, body)
+
+	code, body = serve(t, h, http.MethodPost, "/banners", `{"description":"spring sale"}`)
+	require.Equal(t, http.StatusCreated, code)
+
+	var banner model.Banner
+	require.NoError(t, json.Unmarshal([]byte(body), &banner))
+	require.NotEqual(t, uuid.Nil, banner.ID)
+	require.Equal(t, "spring sale", banner.Description)
+
+	code, body = serve(t, h, http.MethodPut, "/banners/"+banner.ID.String(), `{"id":"`+uuid.NewString()+`","description":"summer sale"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"id":"`+banner.ID.String()+`","description":"summer sale"}`, body, "the ID in the path wins")
+
+	code, body = serve(t, h, http.MethodGet, "/banners/"+banner.ID.String(), "")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"id":"`+banner.ID.String()+`","description":"summer sale"}`, body)
+
+	s.linked[banner.ID] = true
+	code, body = serve(t, h, http.MethodDelete, "/banners/"+banner.ID.String(), "")
+	require.Equal(t, http.StatusConflict, code)
+	require.Contains(t, body, `"banner_linked_to_slot"`)
+
+	s.linked[banner.ID] = false
+	code, body = serve(t, h, http.MethodDelete, "/banners/"+banner.ID.String(), "")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"message":"success"}`, body)
+
+	code, body = serve(t, h, http.MethodGet, "/banners/"+banner.ID.String(), "")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Contains(t, body, `"banner_not_found"`)
+}
+
+func TestBannersInvalidRequests(t *testing.T) {
+	h := NewHandler(newFakeService(), logging.Discard())
+
+	for _, tc := range []struct {
+		method, path, body string
+	}{
+		{method: http.MethodPost, path: "/banners", body: `{"description":`},
+		{method: http.MethodGet, path: "/banners/42"},
+		{method: http.MethodPut, path: "/banners/42", body: `{"description":"spring sale"}`},
+		{method: http.MethodPut, path: "/banners/" + uuid.NewString(), body: `[]`},
+		{method: http.MethodDelete, path: "/banners/42"},
+	} {
+		code, body := serve(t, h, tc.method, tc.path, tc.body)
+		require.Equal(t, http.StatusBadRequest, code, tc.method+" "+tc.path)
+		require.Contains(t, body, `"invalid_request"`)
+	}
+}
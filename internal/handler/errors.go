@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"log/slog"
+	"net/http"
+)
+
+// errInvalidRequest is written when the path, query or body of a request cannot be parsed.
+var errInvalidRequest = stdErrors.New("Invalid request body")
+
+// errorResponse is the body of every failed request. Clients should match Code, Message is for humans.
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type errorMapping struct {
+	status int
+	code   string
+}
+
+var errorMappings = map[error]errorMapping{
+	errInvalidRequest: {http.StatusBadRequest, "invalid_request"},
+
+	errors.ErrBannerNotFound:          {http.StatusNotFound, "banner_not_found"},
+	errors.ErrSlotNotFound:            {http.StatusNotFound, "slot_not_found"},
+	errors.ErrSocialGroupNotFound:     {http.StatusNotFound, "social_group_not_found"},
+	errors.ErrNoOneBannerFoundForSlot: {http.StatusNotFound, "no_banner_in_slot"},
+	errors.ErrBannerNotLinkedToSlot:   {http.StatusNotFound, "banner_not_linked_to_slot"},
+	errors.ErrImpressionNotFound:      {http.StatusNotFound, "impression_not_found"},
+
+	errors.ErrBannerAlreadyLinkedToSlot: {http.StatusConflict, "banner_already_linked_to_slot"},
+	errors.ErrBannerLinkedToSlot:        {http.StatusConflict, "banner_linked_to_slot"},
+	errors.ErrSlotHasBanners:            {http.StatusConflict, "slot_has_banners"},
+	errors.ErrImpressionAlreadyRewarded: {http.StatusConflict, "impression_already_rewarded"},
+
+	errors.ErrUnknownStatDimension:  {http.StatusBadRequest, "unknown_stat_dimension"},
+	errors.ErrRepeatedStatDimension: {http.StatusBadRequest, "repeated_stat_dimension"},
+	errors.ErrTooManyBannerSlots:    {http.StatusBadRequest, "too_many_banner_slots"},
+
+	errors.ErrNegativeConversionValue: {http.StatusBadRequest, "negative_conversion_value"},
+	errors.ErrImpressionRequired:      {http.StatusBadRequest, "impression_required"},
+
+	errors.ErrImpressionExpired: {http.StatusGone, "impression_expired"},
+}
+
+// mapError returns the status and code of a domain error. Unknown errors are internal ones,
+// their messages are logged rather than sent to the client.
+func (h *Handler) mapError(ctx context.Context, err error) (int, *errorResponse) {
+	for domainErr, mapping := range errorMappings {
+		if stdErrors.Is(err, domainErr) {
+			return mapping.status, &errorResponse{Code: mapping.code, Message: domainErr.Error()}
+		}
+	}
+
+	h.logger.ErrorContext(ctx, "internal error", slog.Any("error", err))
+	return http.StatusInternalServerError, &errorResponse{Code: "internal", Message: "internal server error"}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, response := h.mapError(r.Context(), err)
+
+	responseJson, err := json.Marshal(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(responseJson)
+}
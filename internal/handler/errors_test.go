@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	h := NewHandler(nil, logging.Discard())
+
+	for _, tc := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{err: errInvalidRequest, status: http.StatusBadRequest, code: "invalid_request"},
+		{err: errors.ErrBannerNotFound, status: http.StatusNotFound, code: "banner_not_found"},
+		{err: fmt.Errorf("selecting: %w", errors.ErrSlotNotFound), status: http.StatusNotFound, code: "slot_not_found"},
+		{err: errors.ErrBannerAlreadyLinkedToSlot, status: http.StatusConflict, code: "banner_already_linked_to_slot"},
+		{err: errors.ErrUnknownStatDimension, status: http.StatusBadRequest, code: "unknown_stat_dimension"},
+		{err: stdErrors.New(`relation "banner" does not exist`), status: http.StatusInternalServerError, code: "internal"},
+	} {
+		recorder := httptest.NewRecorder()
+		h.writeError(recorder, httptest.NewRequest(http.MethodGet, "/", nil), tc.err)
+
+		require.Equal(t, tc.status, recorder.Code, tc.err)
+
+		var response errorResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response), tc.err)
+		require.Equal(t, tc.code, response.Code)
+		require.NotEmpty(t, response.Message)
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// readinessTimeout bounds the storage check, so a hanging database fails the probe instead of blocking it.
+const readinessTimeout = time.Second
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// Health serves the liveness and readiness probes.
+type Health struct {
+	pinger   pinger
+	logger   *slog.Logger
+	draining atomic.Bool
+}
+
+func NewHealth(pinger pinger, logger *slog.Logger) *Health {
+	return &Health{
+		pinger: pinger,
+		logger: logger,
+	}
+}
+
+func (h *Health) Register(router router) {
+	router.HandlerFunc(http.MethodGet, "/healthz", h.Live)
+	router.HandlerFunc(http.MethodGet, "/readyz", h.Ready)
+}
+
+// SetDraining makes the readiness probe fail, so no new requests are routed to the service while it shuts down.
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process serves requests. It does not touch the storage, so a database outage
+// does not get the service restarted.
+func (h *Health) Live(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, "ok")
+}
+
+// Ready reports whether the service should get requests: it is not draining and the storage can be reached.
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, "draining")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := h.pinger.Ping(ctx); err != nil {
+		h.logger.WarnContext(r.Context(), "readiness check failed", slog.Any("error", err))
+		writeHealth(w, http.StatusServiceUnavailable, "storage unavailable")
+		return
+	}
+
+	writeHealth(w, http.StatusOK, "ready")
+}
+
+func writeHealth(w http.ResponseWriter, status int, healthStatus string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	responseJson, err := json.Marshal(healthResponse{Status: healthStatus})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(responseJson)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHealth(t *testing.T) {
+	var pingErr error
+	health := NewHealth(pingerFunc(func(ctx context.Context) error {
+		return pingErr
+	}), logging.Discard())
+
+	probe := func(handler http.HandlerFunc) (int, string) {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder.Code, recorder.Body.String()
+	}
+
+	code, body := probe(health.Ready)
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"status":"ready"}`, body)
+
+	pingErr = errors.New("connection refused")
+	code, _ = probe(health.Ready)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	code, _ = probe(health.Live)
+	require.Equal(t, http.StatusOK, code, "storage outages do not fail liveness")
+
+	pingErr = nil
+	health.SetDraining()
+	code, body = probe(health.Ready)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.JSONEq(t, `{"status":"draining"}`, body)
+}
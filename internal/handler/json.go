@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+// pathID parses the UUID in the path parameter name.
+func pathID(params httprouter.Params, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(params.ByName(name))
+	if err != nil {
+		return uuid.Nil, errInvalidRequest
+	}
+
+	return id, nil
+}
+
+// decodeBody decodes the JSON body of the request into v.
+func decodeBody(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		return errInvalidRequest
+	}
+
+	return nil
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	responseJson, err := json.Marshal(v)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(responseJson)
+}
+
+// writeSuccess is the response of the requests that change something and have nothing to return.
+func writeSuccess(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message":"success"}`))
+}
@@ -2,148 +2,308 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/logging"
 	"github.com/aakosarev/banner-rotation/internal/model"
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type service interface {
 	AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
 	RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
-	SelectBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID) (*model.Banner, error)
+	AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]*model.BannerSlotResult, error)
+	RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]*model.BannerSlotResult, error)
+	SelectBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID, features model.Features) (*model.Selection, error)
 	AddClick(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) error
+	AddConversion(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID, value float64) error
+	AddImpressionClick(ctx context.Context, impressionID *uuid.UUID) error
+	AddImpressionConversion(ctx context.Context, impressionID *uuid.UUID, value float64) error
+
+	CreateBanner(ctx context.Context, banner *model.Banner) error
+	GetBanner(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error)
+	ListBanners(ctx context.Context) ([]*model.Banner, error)
+	UpdateBanner(ctx context.Context, banner *model.Banner) error
+	DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error
+
+	CreateSlot(ctx context.Context, slot *model.Slot) error
+	GetSlot(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error)
+	ListSlots(ctx context.Context) ([]*model.Slot, error)
+	UpdateSlot(ctx context.Context, slot *model.Slot) error
+	DeleteSlot(ctx context.Context, slotID *uuid.UUID) error
+
+	CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error
+	GetSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error)
+	ListSocialGroups(ctx context.Context) ([]*model.Group, error)
+	UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error
+	DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error
+
+	GetStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error)
+}
+
+// router is satisfied by *httprouter.Router and by the routing.Router that wraps it in middlewares.
+type router interface {
+	GET(path string, handle httprouter.Handle)
+	POST(path string, handle httprouter.Handle)
+	PUT(path string, handle httprouter.Handle)
+	DELETE(path string, handle httprouter.Handle)
+	HandlerFunc(method, path string, handler http.HandlerFunc)
 }
 
 type Handler struct {
 	service service
+	logger  *slog.Logger
 }
 
-func NewHandler(service service) *Handler {
+func NewHandler(service service, logger *slog.Logger) *Handler {
 	return &Handler{
 		service: service,
+		logger:  logger,
 	}
 }
 
-func (h *Handler) Register(router *httprouter.Router) {
+func (h *Handler) Register(router router) {
 	router.HandlerFunc(http.MethodPost, "/banner", h.AddBannerToSlot)
 	router.DELETE("/banner/:banner_id/slot/:slot_id", h.RemoveBannerFromSlot)
 	router.GET("/slot/:slot_id/group/:group_id", h.SelectBanner)
 	router.POST("/banner/:banner_id/slot/:slot_id/group/:group_id/click", h.AddClick)
+	router.POST("/banner/:banner_id/slot/:slot_id/group/:group_id/conversion", h.AddConversion)
+	router.POST("/impressions/:impression_id/click", h.AddImpressionClick)
+	router.POST("/impressions/:impression_id/conversion", h.AddImpressionConversion)
+
+	router.HandlerFunc(http.MethodPost, "/banner-slots", h.AddBannersToSlots)
+	router.HandlerFunc(http.MethodDelete, "/banner-slots", h.RemoveBannersFromSlots)
+
+	router.HandlerFunc(http.MethodPost, "/banners", h.CreateBanner)
+	router.HandlerFunc(http.MethodGet, "/banners", h.ListBanners)
+	router.GET("/banners/:banner_id", h.GetBanner)
+	router.PUT("/banners/:banner_id", h.UpdateBanner)
+	router.DELETE("/banners/:banner_id", h.DeleteBanner)
+
+	router.HandlerFunc(http.MethodPost, "/slots", h.CreateSlot)
+	router.HandlerFunc(http.MethodGet, "/slots", h.ListSlots)
+	router.GET("/slots/:slot_id", h.GetSlot)
+	router.PUT("/slots/:slot_id", h.UpdateSlot)
+	router.DELETE("/slots/:slot_id", h.DeleteSlot)
+
+	router.HandlerFunc(http.MethodPost, "/groups", h.CreateSocialGroup)
+	router.HandlerFunc(http.MethodGet, "/groups", h.ListSocialGroups)
+	router.GET("/groups/:group_id", h.GetSocialGroup)
+	router.PUT("/groups/:group_id", h.UpdateSocialGroup)
+	router.DELETE("/groups/:group_id", h.DeleteSocialGroup)
+
+	router.HandlerFunc(http.MethodGet, "/stats", h.GetStatReports)
 }
 
 func (h *Handler) AddBannerToSlot(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
 	bannerSlot := model.BannerSlot{}
-	err := json.NewDecoder(r.Body).Decode(&bannerSlot)
+	err := decodeBody(r, &bannerSlot)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
+
 	err = h.service.AddBannerToSlot(r.Context(), &bannerSlot.BannerID, &bannerSlot.SlotID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message":"%s"}`, err.Error())))
+		h.writeError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"success"}`))
+
+	writeSuccess(w)
 }
 
 func (h *Handler) RemoveBannerFromSlot(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	w.Header().Set("Content-Type", "application/json")
-
-	bannerID, err := uuid.Parse(params.ByName("banner_id"))
+	bannerID, err := pathID(params, "banner_id")
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
 
-	slotID, err := uuid.Parse(params.ByName("slot_id"))
+	slotID, err := pathID(params, "slot_id")
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
 
 	err = h.service.RemoveBannerFromSlot(r.Context(), &bannerID, &slotID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message":"%s"}`, err.Error())))
+		h.writeError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"success"}`))
+
+	writeSuccess(w)
+}
+
+// selectedBanner is the selected banner and the impression its clicks and conversions should name.
+type selectedBanner struct {
+	*model.Banner
+	ImpressionID uuid.UUID `json:"impression_id"`
 }
 
 func (h *Handler) SelectBanner(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	w.Header().Set("Content-Type", "application/json")
+	slotID, err := pathID(params, "slot_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	socialGroupID, err := pathID(params, "group_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	selection, err := h.service.SelectBanner(r.Context(), &slotID, &socialGroupID, queryFeatures(r))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	logging.AddAccessAttrs(r.Context(),
+		slog.String("banner_id", selection.Banner.ID.String()),
+		slog.String("impression_id", selection.Impression.ID.String()),
+	)
 
-	slotID, err := uuid.Parse(params.ByName("slot_id"))
+	h.writeJSON(w, r, http.StatusOK, &selectedBanner{
+		Banner:       selection.Banner,
+		ImpressionID: selection.Impression.ID,
+	})
+}
+
+func (h *Handler) AddClick(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	bannerID, err := pathID(params, "banner_id")
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
 
-	socialGroupID, err := uuid.Parse(params.ByName("group_id"))
+	slotID, err := pathID(params, "slot_id")
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
-	selectedBanner, err := h.service.SelectBanner(r.Context(), &slotID, &socialGroupID)
+
+	socialGroupID, err := pathID(params, "group_id")
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message":"%s"}`, err.Error())))
+		h.writeError(w, r, err)
 		return
 	}
 
-	selectedBannerJson, err := json.Marshal(selectedBanner)
+	err = h.service.AddClick(r.Context(), &bannerID, &slotID, &socialGroupID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message":"%s"}`, err.Error())))
+		h.writeError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(selectedBannerJson)
+
+	writeSuccess(w)
 }
 
-func (h *Handler) AddClick(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	w.Header().Set("Content-Type", "application/json")
+// AddConversion is the postback of a conversion. The optional value query parameter is its monetary value.
+func (h *Handler) AddConversion(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	bannerID, err := pathID(params, "banner_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
 
-	bannerID, err := uuid.Parse(params.ByName("banner_id"))
+	slotID, err := pathID(params, "slot_id")
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
 
-	slotID, err := uuid.Parse(params.ByName("slot_id"))
+	socialGroupID, err := pathID(params, "group_id")
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, err)
 		return
 	}
 
-	socialGroupID, err := uuid.Parse(params.ByName("group_id"))
+	value, err := queryValue(r)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"message":"Invalid request body"}`))
+		h.writeError(w, r, errInvalidRequest)
 		return
 	}
 
-	err = h.service.AddClick(r.Context(), &bannerID, &slotID, &socialGroupID)
+	err = h.service.AddConversion(r.Context(), &bannerID, &slotID, &socialGroupID, value)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeSuccess(w)
+}
+
+func (h *Handler) AddImpressionClick(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	impressionID, err := pathID(params, "impression_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.AddImpressionClick(r.Context(), &impressionID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeSuccess(w)
+}
+
+// AddImpressionConversion is the postback of a conversion that names the impression it came from.
+func (h *Handler) AddImpressionConversion(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	impressionID, err := pathID(params, "impression_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	value, err := queryValue(r)
+	if err != nil {
+		h.writeError(w, r, errInvalidRequest)
+		return
+	}
+
+	err = h.service.AddImpressionConversion(r.Context(), &impressionID, value)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(fmt.Sprintf(`{"message":"%s"}`, err.Error())))
+		h.writeError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"success"}`))
+
+	writeSuccess(w)
+}
+
+// queryValue reads the optional monetary value of a conversion from the query string.
+func queryValue(r *http.Request) (float64, error) {
+	rawValue := r.URL.Query().Get("value")
+	if rawValue == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errInvalidRequest
+	}
+
+	return value, nil
+}
+
+// queryFeatures reads the request features, such as ?device=mobile, from the query string.
+func queryFeatures(r *http.Request) model.Features {
+	query := r.URL.Query()
+	if len(query) == 0 {
+		return nil
+	}
+
+	features := make(model.Features, len(query))
+	for key := range query {
+		features[key] = query.Get(key)
+	}
+	return features
 }
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+func (h *Handler) CreateSlot(w http.ResponseWriter, r *http.Request) {
+	slot := model.Slot{}
+	err := decodeBody(r, &slot)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.CreateSlot(r.Context(), &slot)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, slot)
+}
+
+func (h *Handler) GetSlot(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	slotID, err := pathID(params, "slot_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	slot, err := h.service.GetSlot(r.Context(), &slotID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, slot)
+}
+
+func (h *Handler) ListSlots(w http.ResponseWriter, r *http.Request) {
+	slots, err := h.service.ListSlots(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if slots == nil {
+		slots = []*model.Slot{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, slots)
+}
+
+func (h *Handler) UpdateSlot(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	slotID, err := pathID(params, "slot_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	slot := model.Slot{}
+	err = decodeBody(r, &slot)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	slot.ID = slotID
+
+	err = h.service.UpdateSlot(r.Context(), &slot)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, slot)
+}
+
+func (h *Handler) DeleteSlot(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	slotID, err := pathID(params, "slot_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.DeleteSlot(r.Context(), &slotID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeSuccess(w)
+}
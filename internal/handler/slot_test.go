@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func (s *fakeService) CreateSlot(ctx context.Context, slot *model.Slot) error {
+	slot.ID = uuid.New()
+	s.slots[slot.ID] = slot
+	return nil
+}
+
+func (s *fakeService) ListSlots(ctx context.Context) ([]*model.Slot, error) {
+	var slots []*model.Slot
+	for _, slot := range s.slots {
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+func (s *fakeService) DeleteSlot(ctx context.Context, slotID *uuid.UUID) error {
+	if _, ok := s.slots[*slotID]; !ok {
+		return errors.ErrSlotNotFound
+	}
+	if s.linked[*slotID] {
+		return errors.ErrSlotHasBanners
+	}
+	delete(s.slots, *slotID)
+	return nil
+}
+
+func TestSlots(t *testing.T) {
+	s := newFakeService()
+	h := NewHandler(s, logging.Discard())
+
+	code, body := serve(t, h, http.MethodPost, "/slots", `{"description":"header"}`)
+	require.Equal(t, http.StatusCreated, code)
+
+	var slot model.Slot
+	require.NoError(t, json.Unmarshal([]byte(body), &slot))
+
+	code, body = serve(t, h, http.MethodGet, "/slots", "")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `[{"id":"`+slot.ID.String()+`","description":"header"}]`, body)
+
+	s.linked[slot.ID] = true
+	code, body = serve(t, h, http.MethodDelete, "/slots/"+slot.ID.String(), "")
+	require.Equal(t, http.StatusConflict, code)
+	require.Contains(t, body, `"slot_has_banners"`)
+
+	missing := uuid.NewString()
+	code, body = serve(t, h, http.MethodDelete, "/slots/"+missing, "")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Contains(t, body, `"slot_not_found"`)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+func (h *Handler) CreateSocialGroup(w http.ResponseWriter, r *http.Request) {
+	socialGroup := model.Group{}
+	err := decodeBody(r, &socialGroup)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.CreateSocialGroup(r.Context(), &socialGroup)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, socialGroup)
+}
+
+func (h *Handler) GetSocialGroup(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	socialGroupID, err := pathID(params, "group_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	socialGroup, err := h.service.GetSocialGroup(r.Context(), &socialGroupID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, socialGroup)
+}
+
+func (h *Handler) ListSocialGroups(w http.ResponseWriter, r *http.Request) {
+	socialGroups, err := h.service.ListSocialGroups(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if socialGroups == nil {
+		socialGroups = []*model.Group{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, socialGroups)
+}
+
+func (h *Handler) UpdateSocialGroup(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	socialGroupID, err := pathID(params, "group_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	socialGroup := model.Group{}
+	err = decodeBody(r, &socialGroup)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	socialGroup.ID = socialGroupID
+
+	err = h.service.UpdateSocialGroup(r.Context(), &socialGroup)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, socialGroup)
+}
+
+func (h *Handler) DeleteSocialGroup(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	socialGroupID, err := pathID(params, "group_id")
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	err = h.service.DeleteSocialGroup(r.Context(), &socialGroupID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeSuccess(w)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+// GetStatReports serves GET /stats?banner_id=&slot_id=&group_id=&group_by=slot,banner,group.
+func (h *Handler) GetStatReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := model.StatFilter{}
+
+	for param, id := range map[string]**uuid.UUID{
+		"banner_id": &filter.BannerID,
+		"slot_id":   &filter.SlotID,
+		"group_id":  &filter.GroupID,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			h.writeError(w, r, errInvalidRequest)
+			return
+		}
+		*id = &parsed
+	}
+
+	if groupBy := query.Get("group_by"); groupBy != "" {
+		filter.GroupBy = strings.Split(groupBy, ",")
+	}
+
+	reports, err := h.service.GetStatReports(r.Context(), &filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if reports == nil {
+		reports = []*model.StatReport{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, reports)
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/routing"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Middleware writes an access log record for every request of the route, with the route pattern,
+// the status, the latency and whatever the handler added with AddAccessAttrs.
+func Middleware(logger *slog.Logger) routing.Middleware {
+	return func(method, route string, next httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+			start := time.Now()
+			access := &accessAttrs{}
+
+			next(w, req.WithContext(context.WithValue(req.Context(), accessAttrsKey{}, access)), params)
+
+			attrs := append([]slog.Attr{
+				slog.String("method", method),
+				slog.String("route", route),
+				slog.Int("status", routing.Status(w)),
+				slog.Duration("latency", time.Since(start)),
+			}, access.get()...)
+
+			logger.LogAttrs(req.Context(), slog.LevelInfo, "access", attrs...)
+		}
+	}
+}
+
+type accessAttrsKey struct{}
+
+type accessAttrs struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+func (a *accessAttrs) get() []slog.Attr {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.attrs
+}
+
+// AddAccessAttrs adds attributes to the access log record of the request of ctx, if it is logged.
+func AddAccessAttrs(ctx context.Context, attrs ...slog.Attr) {
+	access, ok := ctx.Value(accessAttrsKey{}).(*accessAttrs)
+	if !ok {
+		return
+	}
+
+	access.mu.Lock()
+	defer access.mu.Unlock()
+	access.attrs = append(access.attrs, attrs...)
+}
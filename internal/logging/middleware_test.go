@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/routing"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, "info", "json")
+	require.NoError(t, err)
+
+	router := httprouter.New()
+	routing.NewRouter(router, Middleware(logger)).GET("/slot/:slot_id", func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		AddAccessAttrs(r.Context(), slog.String("banner_id", "1"))
+		w.WriteHeader(http.StatusTeapot)
+	})
+	server := RequestIDMiddleware(router)
+
+	for _, tc := range []struct {
+		name      string
+		requestID string
+		propagate bool
+	}{
+		{name: "propagated", requestID: "abc-123", propagate: true},
+		{name: "assigned", requestID: ""},
+		{name: "invalid", requestID: strings.Repeat("a", maxRequestIDLength+1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out.Reset()
+
+			request := httptest.NewRequest(http.MethodGet, "/slot/2", nil)
+			request.Header.Set(RequestIDHeader, tc.requestID)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+
+			requestID := recorder.Header().Get(RequestIDHeader)
+			require.NotEmpty(t, requestID)
+			require.Equal(t, tc.propagate, requestID == tc.requestID)
+
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+			require.Equal(t, "access", record["msg"])
+			require.Equal(t, requestID, record["request_id"])
+			require.Equal(t, "/slot/:slot_id", record["route"])
+			require.Equal(t, float64(http.StatusTeapot), record["status"])
+			require.Equal(t, "1", record["banner_id"])
+			require.Contains(t, record, "latency")
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+const bucketDuration = time.Hour
+
+// BucketStrategy is a Strategy for click-through rates that change over time.
+// It scores banners from hourly statistics instead of lifetime ones.
+type BucketStrategy interface {
+	Strategy
+	// Since returns the start of the oldest bucket the strategy looks at.
+	Since(now time.Time) time.Time
+	SelectFromBuckets(stats []*model.Stat, buckets []*model.StatBucket, now time.Time) *model.Stat
+}
+
+// DiscountedUCB is UCB1 over shows and clicks weighted by discount^age, where age is
+// the number of whole hours since the bucket started. Buckets older than window are ignored,
+// unless window is zero.
+type DiscountedUCB struct {
+	discount float64
+	window   time.Duration
+	name     string
+}
+
+// NewDiscountedUCB returns a DiscountedUCB that weighs an hour-old bucket by discount.
+// Buckets whose weight drops below a thousandth are ignored.
+func NewDiscountedUCB(discount float64) *DiscountedUCB {
+	var window time.Duration
+	if discount > 0 && discount < 1 {
+		window = time.Duration(math.Ceil(math.Log(1e-3)/math.Log(discount))) * bucketDuration
+	}
+
+	return &DiscountedUCB{
+		discount: discount,
+		window:   window,
+		name:     StrategyDiscountedUCB,
+	}
+}
+
+// NewSlidingWindowUCB returns a DiscountedUCB that counts the buckets of the last window in full.
+func NewSlidingWindowUCB(window time.Duration) *DiscountedUCB {
+	return &DiscountedUCB{
+		discount: 1,
+		window:   window,
+		name:     StrategySlidingWindowUCB,
+	}
+}
+
+func (d *DiscountedUCB) Name() string {
+	return d.name
+}
+
+// Select scores banners by their lifetime statistics when no buckets are available.
+func (d *DiscountedUCB) Select(stats []*model.Stat) *model.Stat {
+	return UCB1(stats)
+}
+
+func (d *DiscountedUCB) Since(now time.Time) time.Time {
+	if d.window == 0 {
+		return time.Time{}
+	}
+	return now.Add(-d.window).Truncate(bucketDuration)
+}
+
+func (d *DiscountedUCB) SelectFromBuckets(stats []*model.Stat, buckets []*model.StatBucket, now time.Time) *model.Stat {
+	var (
+		shows      = make(map[*model.Stat]float64, len(stats))
+		clicks     = make(map[*model.Stat]float64, len(stats))
+		byBanner   = make(map[uuid.UUID]*model.Stat, len(stats))
+		totalShows float64
+	)
+
+	for _, stat := range stats {
+		byBanner[stat.BannerID] = stat
+	}
+
+	since := d.Since(now)
+	for _, bucket := range buckets {
+		stat, ok := byBanner[bucket.BannerID]
+		if !ok || bucket.Bucket.Before(since) {
+			continue
+		}
+
+		age := math.Floor(now.Sub(bucket.Bucket).Hours())
+		weight := math.Pow(d.discount, math.Max(age, 0))
+
+		shows[stat] += weight * float64(bucket.Shows)
+		clicks[stat] += weight * float64(bucket.Clicks)
+		totalShows += weight * float64(bucket.Shows)
+	}
+
+	for _, stat := range stats {
+		if shows[stat] == 0 {
+			return stat
+		}
+	}
+
+	var (
+		maxConfidence  = -1.0
+		rotationToShow *model.Stat
+		logTotalShows  = math.Log(math.Max(totalShows, 1))
+	)
+
+	for _, stat := range stats {
+		avgIncome := clicks[stat] / shows[stat]
+		confidence := avgIncome + math.Sqrt(2*logTotalShows/shows[stat])
+		if confidence >= maxConfidence {
+			maxConfidence = confidence
+			rotationToShow = stat
+		}
+	}
+	return rotationToShow
+}
@@ -0,0 +1,44 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDiscountedUCB(t *testing.T) {
+	now := time.Date(2022, 12, 14, 13, 30, 0, 0, time.UTC)
+	hour := now.Truncate(time.Hour)
+
+	stale := &model.Stat{BannerID: uuid.New(), Shows: 14000, Clicks: 1040}
+	fresh := &model.Stat{BannerID: uuid.New(), Shows: 5000, Clicks: 150}
+	stats := []*model.Stat{stale, fresh}
+
+	// the stale banner was great a month ago and is poor now
+	buckets := []*model.StatBucket{
+		{BannerID: stale.BannerID, Bucket: hour.Add(-30 * 24 * time.Hour), Shows: 9000, Clicks: 990},
+		{BannerID: stale.BannerID, Bucket: hour, Shows: 5000, Clicks: 50},
+		{BannerID: fresh.BannerID, Bucket: hour, Shows: 5000, Clicks: 150},
+	}
+
+	t.Run("lifetime statistics favour the stale banner", func(t *testing.T) {
+		require.Equal(t, stale, UCB1(stats))
+	})
+
+	t.Run("discounted statistics favour the fresh banner", func(t *testing.T) {
+		require.Equal(t, fresh, NewDiscountedUCB(0.99).SelectFromBuckets(stats, buckets, now))
+	})
+
+	t.Run("sliding window ignores old buckets", func(t *testing.T) {
+		strategy := NewSlidingWindowUCB(7 * 24 * time.Hour)
+
+		require.Equal(t, hour.Add(-7*24*time.Hour), strategy.Since(now))
+		require.Equal(t, fresh, strategy.SelectFromBuckets(stats, buckets, now))
+	})
+
+	t.Run("banners without recent shows are explored", func(t *testing.T) {
+		require.Equal(t, stale, NewSlidingWindowUCB(time.Hour).SelectFromBuckets(stats, buckets[2:], now))
+	})
+}
@@ -0,0 +1,84 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math/rand"
+	"sync"
+)
+
+// EpsilonGreedy shows a random banner with probability epsilon and the banner with the best
+// click-through rate otherwise.
+type EpsilonGreedy struct {
+	mu      sync.Mutex
+	rnd     *rand.Rand
+	epsilon float64
+	decay   float64
+	name    string
+}
+
+func NewEpsilonGreedy(rnd *rand.Rand, epsilon float64) *EpsilonGreedy {
+	return &EpsilonGreedy{
+		rnd:     rnd,
+		epsilon: epsilon,
+		name:    StrategyEpsilonGreedy,
+	}
+}
+
+// NewDecayingEpsilonGreedy returns an EpsilonGreedy whose exploration probability is
+// epsilon * decay / (decay + totalShows), so it halves once the slot has decay shows in total.
+func NewDecayingEpsilonGreedy(rnd *rand.Rand, epsilon, decay float64) *EpsilonGreedy {
+	return &EpsilonGreedy{
+		rnd:     rnd,
+		epsilon: epsilon,
+		decay:   decay,
+		name:    StrategyDecayingEpsilonGreedy,
+	}
+}
+
+func (e *EpsilonGreedy) Name() string {
+	return e.name
+}
+
+func (e *EpsilonGreedy) Epsilon(totalShows int64) float64 {
+	if e.decay <= 0 {
+		return e.epsilon
+	}
+	return e.epsilon * e.decay / (e.decay + float64(totalShows))
+}
+
+func (e *EpsilonGreedy) Select(stats []*model.Stat) *model.Stat {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	var totalShows int64
+	for _, stat := range stats {
+		totalShows += int64(stat.Shows)
+	}
+
+	e.mu.Lock()
+	explore := e.rnd.Float64() < e.Epsilon(totalShows)
+	index := e.rnd.Intn(len(stats))
+	e.mu.Unlock()
+
+	if explore {
+		return stats[index]
+	}
+
+	var (
+		maxAvgIncome   = -1.0
+		rotationToShow *model.Stat
+	)
+
+	for _, stat := range stats {
+		var avgIncome float64
+		if stat.Shows > 0 {
+			avgIncome = float64(stat.Clicks) / float64(stat.Shows)
+		}
+		if avgIncome > maxAvgIncome {
+			maxAvgIncome = avgIncome
+			rotationToShow = stat
+		}
+	}
+	return rotationToShow
+}
@@ -0,0 +1,59 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestEpsilonGreedy(t *testing.T) {
+	newStats := func() []*model.Stat {
+		stats := make([]*model.Stat, 0, 10)
+		for i := 1; i <= cap(stats); i++ {
+			stats = append(stats, &model.Stat{
+				BannerID: uuid.New(),
+				SlotID:   uuid.New(),
+				GroupID:  uuid.New(),
+				Shows:    1000,
+				Clicks:   10,
+			})
+		}
+		stats[3].Clicks = 50
+		return stats
+	}
+
+	t.Run("without exploration the best banner is always shown", func(t *testing.T) {
+		strategy := NewEpsilonGreedy(rand.New(rand.NewSource(1)), 0)
+		stats := newStats()
+
+		for i := 1; i <= 200; i++ {
+			require.Equal(t, stats[3], strategy.Select(stats))
+		}
+	})
+
+	t.Run("exploration happens as often as configured", func(t *testing.T) {
+		strategy := NewEpsilonGreedy(rand.New(rand.NewSource(1)), 0.1)
+		stats := newStats()
+
+		const rounds = 10000
+		var explored int
+		for i := 1; i <= rounds; i++ {
+			if strategy.Select(stats) != stats[3] {
+				explored++
+			}
+		}
+
+		// nine out of ten explorations land on another banner
+		require.InDelta(t, 0.09, float64(explored)/rounds, 0.01)
+	})
+
+	t.Run("decaying epsilon halves after decay shows", func(t *testing.T) {
+		strategy := NewDecayingEpsilonGreedy(rand.New(rand.NewSource(1)), 0.2, 1000)
+
+		require.Equal(t, 0.2, strategy.Epsilon(0))
+		require.Equal(t, 0.1, strategy.Epsilon(1000))
+		require.Less(t, strategy.Epsilon(100000), 0.01)
+	})
+}
@@ -0,0 +1,35 @@
+package mab
+
+import "github.com/aakosarev/banner-rotation/internal/model"
+
+// rewardStater is a strategy that optimises a reward other than clicks, see Rewarded.
+type rewardStater interface {
+	RewardStat(stat *model.Stat) *model.Stat
+}
+
+// IsExploration tells whether the strategy explores by selecting the stat: the selected banner has not
+// been shown yet or earns a lower reward per show than the best banner. It works for any strategy, since
+// it only looks at what was selected and at the reward the strategy optimises.
+func IsExploration(strategy Strategy, stats []*model.Stat, selected *model.Stat) bool {
+	if selected.Shows == 0 {
+		return true
+	}
+
+	rewardStat := func(stat *model.Stat) *model.Stat { return stat }
+	if rewarded, ok := strategy.(rewardStater); ok {
+		rewardStat = rewarded.RewardStat
+	}
+
+	selectedRate := rewardRate(rewardStat(selected))
+	for _, stat := range stats {
+		if stat.Shows > 0 && rewardRate(rewardStat(stat)) > selectedRate {
+			return true
+		}
+	}
+
+	return false
+}
+
+func rewardRate(stat *model.Stat) float64 {
+	return float64(stat.Clicks) / float64(stat.Shows)
+}
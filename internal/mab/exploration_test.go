@@ -0,0 +1,29 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestIsExploration(t *testing.T) {
+	best := &model.Stat{Shows: 100, Clicks: 10}
+	worse := &model.Stat{Shows: 100, Clicks: 5, Conversions: 5}
+	unseen := &model.Stat{}
+	stats := []*model.Stat{best, worse, unseen}
+
+	strategy := StrategyFunc(UCB1)
+	require.False(t, IsExploration(strategy, stats, best))
+	require.True(t, IsExploration(strategy, stats, worse))
+	require.True(t, IsExploration(strategy, stats, unseen))
+
+	// the banner with fewer clicks converts better
+	conversions, err := New(StrategyUCB1, Options{Reward: RewardConversion})
+	require.NoError(t, err)
+	require.True(t, IsExploration(conversions, stats, best))
+	require.False(t, IsExploration(conversions, stats, worse))
+
+	buckets, err := New(StrategyDiscountedUCB, Options{Discount: 0.99, Reward: RewardConversion})
+	require.NoError(t, err)
+	require.False(t, IsExploration(buckets, stats, worse))
+}
@@ -0,0 +1,61 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math"
+)
+
+const klucbPrecision = 1e-6
+
+// KLUCB scores every banner with the largest click-through rate q that satisfies
+// shows * KL(ctr, q) <= ln(totalShows), where KL is the Bernoulli Kullback-Leibler divergence.
+func KLUCB(stats []*model.Stat) *model.Stat {
+
+	var (
+		maxConfidence  = -1.0
+		rotationToShow *model.Stat
+		totalShows     int64
+	)
+
+	for _, stat := range stats {
+		if stat.Shows == 0 {
+			return stat
+		}
+		totalShows += int64(stat.Shows)
+	}
+
+	logTotalShows := math.Log(float64(totalShows))
+
+	for _, stat := range stats {
+		shows := float64(stat.Shows)
+		avgIncome := math.Min(float64(stat.Clicks)/shows, 1)
+		confidence := klUpperBound(avgIncome, logTotalShows/shows)
+		if confidence >= maxConfidence {
+			maxConfidence = confidence
+			rotationToShow = stat
+		}
+	}
+	return rotationToShow
+}
+
+// klUpperBound finds the largest q in [p, 1] with KL(p, q) <= level by bisection.
+func klUpperBound(p, level float64) float64 {
+	low, high := p, 1.0
+	for high-low > klucbPrecision {
+		mid := (low + high) / 2
+		if bernoulliKL(p, mid) > level {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return low
+}
+
+func bernoulliKL(p, q float64) float64 {
+	const eps = 1e-15
+
+	p = math.Min(math.Max(p, eps), 1-eps)
+	q = math.Min(math.Max(q, eps), 1-eps)
+	return p*math.Log(p/q) + (1-p)*math.Log((1-p)/(1-q))
+}
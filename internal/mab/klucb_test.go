@@ -0,0 +1,38 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestKLUpperBound(t *testing.T) {
+	for _, p := range []float64{0, 0.01, 0.3, 0.99} {
+		bound := klUpperBound(p, 0.05)
+
+		require.GreaterOrEqual(t, bound, p)
+		require.LessOrEqual(t, bound, 1.0)
+		require.InDelta(t, 0.05, bernoulliKL(p, bound), 1e-3)
+	}
+
+	// the bound tightens as shows grow
+	require.Less(t, klUpperBound(0.01, 0.001), klUpperBound(0.01, 0.01))
+}
+
+func TestLowCTRBounds(t *testing.T) {
+	stats := []*model.Stat{
+		{BannerID: uuid.New(), Shows: 5000, Clicks: 50},
+		{BannerID: uuid.New(), Shows: 5000, Clicks: 75},
+	}
+
+	// around a 1% click-through rate the bounds are narrow enough to prefer the better banner
+	require.Equal(t, stats[1], UCB1Tuned(stats))
+	require.Equal(t, stats[1], KLUCB(stats))
+
+	for _, strategy := range []func([]*model.Stat) *model.Stat{UCB1Tuned, KLUCB} {
+		stats[0].Shows = 0
+		require.Equal(t, stats[0], strategy(stats))
+		stats[0].Shows = 5000
+	}
+}
@@ -0,0 +1,164 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+const defaultLinUCBDimension = 64
+
+// ContextualStrategy is a Strategy that learns from request features, so what it learns
+// in one social group is shared with the others.
+type ContextualStrategy interface {
+	Strategy
+	SelectWithFeatures(stats []*model.Stat, features model.Features) *model.Stat
+	ObserveShow(stat *model.Stat, features model.Features)
+	ObserveClick(stat *model.Stat, features model.Features)
+}
+
+type linArm struct {
+	// aInv is the inverse of the design matrix, kept up to date with the Sherman-Morrison formula.
+	aInv [][]float64
+	b    []float64
+}
+
+type armKey struct {
+	slotID   uuid.UUID
+	bannerID uuid.UUID
+}
+
+// LinUCB is the disjoint linear UCB algorithm. Every banner in a slot has its own ridge regression of
+// clicks on hashed request features. The model is kept in memory only and starts over after a restart.
+type LinUCB struct {
+	mu        sync.Mutex
+	alpha     float64
+	dimension int
+	arms      map[armKey]*linArm
+}
+
+func NewLinUCB(alpha float64, dimension int) *LinUCB {
+	if dimension < 2 {
+		dimension = defaultLinUCBDimension
+	}
+
+	return &LinUCB{
+		alpha:     alpha,
+		dimension: dimension,
+		arms:      make(map[armKey]*linArm),
+	}
+}
+
+func (l *LinUCB) Name() string {
+	return StrategyLinUCB
+}
+
+// Select falls back to UCB1 on the statistics of the social group when the request has no features.
+func (l *LinUCB) Select(stats []*model.Stat) *model.Stat {
+	return UCB1(stats)
+}
+
+func (l *LinUCB) SelectWithFeatures(stats []*model.Stat, features model.Features) *model.Stat {
+	x := l.vector(features)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var (
+		maxConfidence  = math.Inf(-1)
+		rotationToShow *model.Stat
+	)
+
+	for _, stat := range stats {
+		arm := l.arm(stat)
+
+		aInvX := mulVec(arm.aInv, x)
+		theta := mulVec(arm.aInv, arm.b)
+		confidence := dot(theta, x) + l.alpha*math.Sqrt(dot(x, aInvX))
+		if confidence > maxConfidence {
+			maxConfidence = confidence
+			rotationToShow = stat
+		}
+	}
+	return rotationToShow
+}
+
+// ObserveShow records a show with no click yet.
+func (l *LinUCB) ObserveShow(stat *model.Stat, features model.Features) {
+	x := l.vector(features)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	arm := l.arm(stat)
+
+	aInvX := mulVec(arm.aInv, x)
+	denominator := 1 + dot(x, aInvX)
+	for i := range arm.aInv {
+		for j := range arm.aInv[i] {
+			arm.aInv[i][j] -= aInvX[i] * aInvX[j] / denominator
+		}
+	}
+}
+
+// ObserveClick turns the reward of a show with the same features from zero into one.
+func (l *LinUCB) ObserveClick(stat *model.Stat, features model.Features) {
+	x := l.vector(features)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	arm := l.arm(stat)
+	for i := range arm.b {
+		arm.b[i] += x[i]
+	}
+}
+
+func (l *LinUCB) arm(stat *model.Stat) *linArm {
+	key := armKey{slotID: stat.SlotID, bannerID: stat.BannerID}
+
+	arm, ok := l.arms[key]
+	if !ok {
+		arm = &linArm{
+			aInv: make([][]float64, l.dimension),
+			b:    make([]float64, l.dimension),
+		}
+		for i := range arm.aInv {
+			arm.aInv[i] = make([]float64, l.dimension)
+			arm.aInv[i][i] = 1
+		}
+		l.arms[key] = arm
+	}
+	return arm
+}
+
+// vector hashes every feature into one of the coordinates after the bias term.
+func (l *LinUCB) vector(features model.Features) []float64 {
+	x := make([]float64, l.dimension)
+	x[0] = 1
+
+	for key, value := range features {
+		h := fnv.New32a()
+		h.Write([]byte(key + "=" + value))
+		x[1+int(h.Sum32()%uint32(l.dimension-1))] += 1
+	}
+	return x
+}
+
+func mulVec(m [][]float64, v []float64) []float64 {
+	result := make([]float64, len(m))
+	for i := range m {
+		result[i] = dot(m[i], v)
+	}
+	return result
+}
+
+func dot(a, b []float64) float64 {
+	var result float64
+	for i := range a {
+		result += a[i] * b[i]
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestLinUCB(t *testing.T) {
+	strategy := NewLinUCB(0.5, 16)
+	rnd := rand.New(rand.NewSource(1))
+
+	slotID := uuid.New()
+	mobileBanner := &model.Stat{BannerID: uuid.New(), SlotID: slotID}
+	desktopBanner := &model.Stat{BannerID: uuid.New(), SlotID: slotID}
+	stats := []*model.Stat{mobileBanner, desktopBanner}
+
+	clickProbability := map[string]map[*model.Stat]float64{
+		"mobile":  {mobileBanner: 0.3, desktopBanner: 0.05},
+		"desktop": {mobileBanner: 0.05, desktopBanner: 0.3},
+	}
+
+	// only the large social group is seen while learning
+	for i := 0; i < 4000; i++ {
+		device := "mobile"
+		if i%2 == 1 {
+			device = "desktop"
+		}
+		features := model.Features{"group": "large", "device": device}
+
+		stat := strategy.SelectWithFeatures(stats, features)
+		strategy.ObserveShow(stat, features)
+		if rnd.Float64() < clickProbability[device][stat] {
+			strategy.ObserveClick(stat, features)
+		}
+	}
+
+	t.Run("the banner depends on the features", func(t *testing.T) {
+		require.Equal(t, mobileBanner, strategy.SelectWithFeatures(stats, model.Features{"group": "large", "device": "mobile"}))
+		require.Equal(t, desktopBanner, strategy.SelectWithFeatures(stats, model.Features{"group": "large", "device": "desktop"}))
+	})
+
+	t.Run("a new social group does not start from zero", func(t *testing.T) {
+		require.Equal(t, mobileBanner, strategy.SelectWithFeatures(stats, model.Features{"group": "small", "device": "mobile"}))
+		require.Equal(t, desktopBanner, strategy.SelectWithFeatures(stats, model.Features{"group": "small", "device": "desktop"}))
+	})
+
+	t.Run("without features the social group statistics are used", func(t *testing.T) {
+		unshown := &model.Stat{BannerID: uuid.New(), SlotID: slotID}
+		require.Equal(t, unshown, strategy.Select([]*model.Stat{{Shows: 10, Clicks: 5}, unshown}))
+	})
+}
@@ -0,0 +1,101 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math"
+	"time"
+)
+
+const (
+	RewardClick      = "click"
+	RewardConversion = "conversion"
+	RewardRevenue    = "revenue"
+)
+
+// Rewarded makes a strategy optimise conversions or revenue instead of clicks. Strategies score the
+// clicks of the stats, so Rewarded hands them copies whose clicks are the reward. The revenue reward
+// is the revenue divided by revenueScale, the revenue worth one click. Set it to the value of the most
+// valuable conversion, so that a show earns at most one click.
+type Rewarded struct {
+	strategy     Strategy
+	reward       string
+	revenueScale float64
+}
+
+// rewardedBuckets is a Rewarded BucketStrategy.
+type rewardedBuckets struct {
+	*Rewarded
+	strategy BucketStrategy
+}
+
+// NewRewarded wraps the strategy. The result is a BucketStrategy if the strategy is one.
+func NewRewarded(strategy Strategy, reward string, revenueScale float64) Strategy {
+	rewarded := &Rewarded{
+		strategy:     strategy,
+		reward:       reward,
+		revenueScale: revenueScale,
+	}
+
+	if bucketStrategy, ok := strategy.(BucketStrategy); ok {
+		return &rewardedBuckets{Rewarded: rewarded, strategy: bucketStrategy}
+	}
+	return rewarded
+}
+
+// Name returns the name of the wrapped strategy.
+func (r *Rewarded) Name() string {
+	return NameOf(r.strategy)
+}
+
+func (r *Rewarded) Select(stats []*model.Stat) *model.Stat {
+	return selectFromCopies(stats, r.RewardStat, r.strategy.Select)
+}
+
+func (r *rewardedBuckets) Since(now time.Time) time.Time {
+	return r.strategy.Since(now)
+}
+
+func (r *rewardedBuckets) SelectFromBuckets(stats []*model.Stat, buckets []*model.StatBucket, now time.Time) *model.Stat {
+	rewardBuckets := make([]*model.StatBucket, len(buckets))
+	for i, bucket := range buckets {
+		rewardBucket := *bucket
+		rewardBucket.Clicks = r.rewardOf(bucket.Clicks, bucket.Conversions, bucket.Revenue)
+		rewardBuckets[i] = &rewardBucket
+	}
+
+	return selectFromCopies(stats, r.RewardStat, func(rewardStats []*model.Stat) *model.Stat {
+		return r.strategy.SelectFromBuckets(rewardStats, rewardBuckets, now)
+	})
+}
+
+// RewardStat returns a copy of the stat whose clicks are the reward.
+func (r *Rewarded) RewardStat(stat *model.Stat) *model.Stat {
+	rewardStat := *stat
+	rewardStat.Clicks = r.rewardOf(stat.Clicks, stat.Conversions, stat.Revenue)
+	return &rewardStat
+}
+
+func (r *Rewarded) rewardOf(clicks, conversions int, revenue float64) int {
+	switch r.reward {
+	case RewardConversion:
+		return conversions
+	case RewardRevenue:
+		return int(math.Round(revenue / r.revenueScale))
+	default:
+		return clicks
+	}
+}
+
+// selectFromCopies lets choose select from copies of the stats made by copyStat, and returns the original
+// of the copy it selected.
+func selectFromCopies(stats []*model.Stat, copyStat func(stat *model.Stat) *model.Stat,
+	choose func(copies []*model.Stat) *model.Stat) *model.Stat {
+	copies := make([]*model.Stat, len(stats))
+	originals := make(map[*model.Stat]*model.Stat, len(stats))
+	for i, stat := range stats {
+		copies[i] = copyStat(stat)
+		originals[copies[i]] = stat
+	}
+
+	return originals[choose(copies)]
+}
@@ -0,0 +1,64 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRewarded(t *testing.T) {
+	// the clickbait banner gets clicked a lot and never converts
+	clickbait := &model.Stat{BannerID: uuid.New(), Shows: 1000, Clicks: 200, Conversions: 1, Revenue: 10}
+	cheap := &model.Stat{BannerID: uuid.New(), Shows: 1000, Clicks: 50, Conversions: 20, Revenue: 100}
+	premium := &model.Stat{BannerID: uuid.New(), Shows: 1000, Clicks: 40, Conversions: 10, Revenue: 1000}
+	stats := []*model.Stat{clickbait, cheap, premium}
+
+	for _, tc := range []struct {
+		reward   string
+		selected *model.Stat
+	}{
+		{reward: RewardClick, selected: clickbait},
+		{reward: RewardConversion, selected: cheap},
+		{reward: RewardRevenue, selected: premium},
+	} {
+		t.Run(tc.reward, func(t *testing.T) {
+			strategy, err := New(StrategyUCB1, Options{Reward: tc.reward, RevenueScale: 100})
+			require.NoError(t, err)
+			require.Equal(t, StrategyUCB1, NameOf(strategy))
+
+			require.Same(t, tc.selected, strategy.Select(stats))
+			require.Equal(t, 200, clickbait.Clicks, "the stats are not changed")
+		})
+	}
+
+	t.Run("buckets", func(t *testing.T) {
+		strategy, err := New(StrategySlidingWindowUCB, Options{Window: time.Hour, Reward: RewardRevenue, RevenueScale: 100})
+		require.NoError(t, err)
+
+		bucketStrategy, ok := strategy.(BucketStrategy)
+		require.True(t, ok)
+
+		now := time.Now()
+		var buckets []*model.StatBucket
+		for _, stat := range stats {
+			buckets = append(buckets, &model.StatBucket{
+				BannerID:    stat.BannerID,
+				Bucket:      now.Truncate(time.Hour),
+				Shows:       stat.Shows,
+				Clicks:      stat.Clicks,
+				Conversions: stat.Conversions,
+				Revenue:     stat.Revenue,
+			})
+		}
+
+		require.Same(t, premium, bucketStrategy.SelectFromBuckets(stats, buckets, now))
+	})
+
+	_, err := New(StrategyUCB1, Options{Reward: RewardRevenue})
+	require.Error(t, err, "revenue needs a scale")
+
+	_, err = New(StrategyUCB1, Options{Reward: "likes"})
+	require.Error(t, err)
+}
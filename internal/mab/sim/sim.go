@@ -0,0 +1,74 @@
+// Package sim replays synthetic click probabilities against a bandit strategy
+// and measures how much it loses to always showing the best banner.
+package sim
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"math/rand"
+)
+
+type Result struct {
+	// Shows is the number of times every banner was selected, in the order of the probabilities.
+	Shows  []int
+	Clicks int
+	// Regret is the cumulative expected regret after every round.
+	Regret []float64
+}
+
+// TotalRegret returns the cumulative expected regret after the last round.
+func (r *Result) TotalRegret() float64 {
+	if len(r.Regret) == 0 {
+		return 0
+	}
+	return r.Regret[len(r.Regret)-1]
+}
+
+// Run shows one of the banners with the given click probabilities in every round,
+// as selected by the strategy, and simulates the click with rnd.
+func Run(strategy mab.Strategy, probabilities []float64, rounds int, rnd *rand.Rand) *Result {
+	var (
+		slotID   = uuid.New()
+		groupID  = uuid.New()
+		stats    = make([]*model.Stat, len(probabilities))
+		indexes  = make(map[*model.Stat]int, len(probabilities))
+		bestProb float64
+	)
+
+	for i, probability := range probabilities {
+		stats[i] = &model.Stat{
+			BannerID: uuid.New(),
+			SlotID:   slotID,
+			GroupID:  groupID,
+		}
+		indexes[stats[i]] = i
+
+		if probability > bestProb {
+			bestProb = probability
+		}
+	}
+
+	result := &Result{
+		Shows:  make([]int, len(probabilities)),
+		Regret: make([]float64, 0, rounds),
+	}
+
+	var regret float64
+	for round := 0; round < rounds; round++ {
+		stat := strategy.Select(stats)
+		i := indexes[stat]
+
+		stat.Shows++
+		result.Shows[i]++
+		if rnd.Float64() < probabilities[i] {
+			stat.Clicks++
+			result.Clicks++
+		}
+
+		regret += bestProb - probabilities[i]
+		result.Regret = append(result.Regret, regret)
+	}
+
+	return result
+}
@@ -0,0 +1,52 @@
+package sim
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	const rounds = 20000
+
+	probabilities := []float64{0.02, 0.05, 0.1, 0.03}
+
+	random := rand.New(rand.NewSource(1))
+	uniform := mab.StrategyFunc(func(stats []*model.Stat) *model.Stat {
+		return stats[random.Intn(len(stats))]
+	})
+	uniformResult := Run(uniform, probabilities, rounds, rand.New(rand.NewSource(1)))
+
+	strategies := map[string]mab.Strategy{
+		mab.StrategyUCB1:                  mab.StrategyFunc(mab.UCB1),
+		mab.StrategyUCB1Tuned:             mab.StrategyFunc(mab.UCB1Tuned),
+		mab.StrategyKLUCB:                 mab.StrategyFunc(mab.KLUCB),
+		mab.StrategyThompson:              mab.NewThompsonSampling(rand.New(rand.NewSource(1))),
+		mab.StrategyEpsilonGreedy:         mab.NewEpsilonGreedy(rand.New(rand.NewSource(1)), 0.1),
+		mab.StrategyDecayingEpsilonGreedy: mab.NewDecayingEpsilonGreedy(rand.New(rand.NewSource(1)), 0.2, 1000),
+	}
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			result := Run(strategy, probabilities, rounds, rand.New(rand.NewSource(1)))
+
+			require.Len(t, result.Regret, rounds)
+			require.Less(t, result.TotalRegret(), uniformResult.TotalRegret())
+
+			// the best banner is shown more often than any other
+			for i, shows := range result.Shows {
+				if i != 2 {
+					require.Greater(t, result.Shows[2], shows)
+				}
+			}
+
+			// the regret grows slower once the strategy has learned the best banner
+			half := result.Regret[rounds/2-1]
+			require.Less(t, result.TotalRegret()-half, half)
+
+			t.Logf("regret %.1f (uniform %.1f), clicks %d", result.TotalRegret(), uniformResult.TotalRegret(), result.Clicks)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package mab
+
+import (
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	StrategyUCB1      = "ucb1"
+	StrategyUCB1Tuned = "ucb1-tuned"
+	StrategyKLUCB     = "kl-ucb"
+	StrategyThompson  = "thompson"
+
+	StrategyEpsilonGreedy         = "epsilon-greedy"
+	StrategyDecayingEpsilonGreedy = "decaying-epsilon-greedy"
+
+	StrategyDiscountedUCB    = "discounted-ucb"
+	StrategySlidingWindowUCB = "sliding-window-ucb"
+
+	StrategyLinUCB = "linucb"
+)
+
+// Strategy selects the banner to show from the statistics of the banners linked to a slot.
+type Strategy interface {
+	Select(stats []*model.Stat) *model.Stat
+}
+
+// StrategyFunc adapts an ordinary function to the Strategy interface.
+type StrategyFunc func(stats []*model.Stat) *model.Stat
+
+func (f StrategyFunc) Select(stats []*model.Stat) *model.Stat {
+	return f(stats)
+}
+
+// Named is implemented by the strategies returned by New.
+type Named interface {
+	Name() string
+}
+
+// NameOf returns the name the strategy was registered under, or "custom" for other strategies.
+func NameOf(strategy Strategy) string {
+	if named, ok := strategy.(Named); ok {
+		return named.Name()
+	}
+	return "custom"
+}
+
+// namedFunc names the strategies that are plain functions.
+type namedFunc struct {
+	StrategyFunc
+	name string
+}
+
+func (f namedFunc) Name() string {
+	return f.name
+}
+
+type Options struct {
+	// Rand is the random source of randomized strategies. A time-seeded source is used if it is nil.
+	Rand *rand.Rand
+	// Epsilon is the exploration probability of the epsilon-greedy strategies.
+	Epsilon float64
+	// EpsilonDecay is the number of shows after which the decaying epsilon is halved.
+	EpsilonDecay float64
+	// Discount is the weight an hour-old show or click has for the discounted strategy.
+	Discount float64
+	// Window is how far back the sliding-window strategy looks.
+	Window time.Duration
+	// LinUCBAlpha scales the exploration term of LinUCB.
+	LinUCBAlpha float64
+	// LinUCBDimension is the length of the hashed feature vector of LinUCB.
+	LinUCBDimension int
+	// Prior and Budget warm-start new banners. LinUCB learns from features and rejects them.
+	Prior  Prior
+	Budget Budget
+	// Reward is what the strategy optimises: RewardClick, RewardConversion or RewardRevenue. LinUCB
+	// learns from the clicks it observes and rejects the others.
+	Reward string
+	// RevenueScale is the revenue worth one click, at best the value of the most valuable conversion.
+	// See Rewarded.
+	RevenueScale float64
+}
+
+// New returns the strategy registered under the given name, warm-started if the options have a prior
+// or a budget, and optimising the reward of the options.
+func New(name string, opts Options) (Strategy, error) {
+	rnd := opts.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	switch opts.Prior.Kind {
+	case "", PriorNone:
+	case PriorOptimistic, PriorSlotAverage:
+		if opts.Prior.CTR < 0 || opts.Prior.CTR > 1 {
+			return nil, fmt.Errorf("prior needs a click-through rate between 0 and 1, got %v", opts.Prior.CTR)
+		}
+		if opts.Prior.CTR > 0 && float64(opts.Prior.Shows)*opts.Prior.CTR < 1 {
+			return nil, fmt.Errorf("prior of %d shows cannot credit a click at %v, it needs at least %v shows",
+				opts.Prior.Shows, opts.Prior.CTR, math.Ceil(1/opts.Prior.CTR))
+		}
+	default:
+		return nil, fmt.Errorf("unknown prior %q", opts.Prior.Kind)
+	}
+
+	switch opts.Reward {
+	case "", RewardClick, RewardConversion:
+	case RewardRevenue:
+		if opts.RevenueScale <= 0 {
+			return nil, fmt.Errorf("revenue reward needs a positive revenue scale")
+		}
+	default:
+		return nil, fmt.Errorf("unknown reward %q", opts.Reward)
+	}
+
+	strategy, err := newStrategy(name, opts, rnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := strategy.(ContextualStrategy); ok {
+		// contextual strategies learn from the clicks on the features, they cannot be wrapped
+		switch {
+		case opts.Prior.enabled():
+			return nil, fmt.Errorf("strategy %s does not support a prior", name)
+		case opts.Budget.Shows > 0:
+			return nil, fmt.Errorf("strategy %s does not support an exploration budget", name)
+		case opts.Reward != "" && opts.Reward != RewardClick:
+			return nil, fmt.Errorf("strategy %s only optimises clicks, not %s", name, opts.Reward)
+		}
+		return strategy, nil
+	}
+
+	if opts.Prior.enabled() || opts.Budget.Shows > 0 {
+		// the strategy locks its source with its own mutex, so the budget needs a source of its own
+		budgetRnd := rand.New(rand.NewSource(rnd.Int63()))
+
+		strategy = NewWarmStart(strategy, budgetRnd, opts.Prior, opts.Budget)
+	}
+
+	// the prior is in units of the reward, so the reward wraps the warm start
+	if opts.Reward != "" && opts.Reward != RewardClick {
+		strategy = NewRewarded(strategy, opts.Reward, opts.RevenueScale)
+	}
+
+	return strategy, nil
+}
+
+func newStrategy(name string, opts Options, rnd *rand.Rand) (Strategy, error) {
+	switch name {
+	case StrategyUCB1:
+		return namedFunc{StrategyFunc: UCB1, name: name}, nil
+	case StrategyUCB1Tuned:
+		return namedFunc{StrategyFunc: UCB1Tuned, name: name}, nil
+	case StrategyKLUCB:
+		return namedFunc{StrategyFunc: KLUCB, name: name}, nil
+	case StrategyThompson:
+		return NewThompsonSampling(rnd), nil
+	case StrategyEpsilonGreedy:
+		if opts.Epsilon < 0 || opts.Epsilon > 1 {
+			return nil, fmt.Errorf("%s needs an epsilon between 0 and 1, got %v", name, opts.Epsilon)
+		}
+		return NewEpsilonGreedy(rnd, opts.Epsilon), nil
+	case StrategyDecayingEpsilonGreedy:
+		if opts.Epsilon < 0 || opts.Epsilon > 1 {
+			return nil, fmt.Errorf("%s needs an epsilon between 0 and 1, got %v", name, opts.Epsilon)
+		}
+		if opts.EpsilonDecay <= 0 {
+			return nil, fmt.Errorf("%s needs a positive epsilon decay, got %v", name, opts.EpsilonDecay)
+		}
+		return NewDecayingEpsilonGreedy(rnd, opts.Epsilon, opts.EpsilonDecay), nil
+	case StrategyDiscountedUCB:
+		// a discount of 0 only counts the current hour, and 1 is plain UCB1
+		if opts.Discount <= 0 || opts.Discount >= 1 {
+			return nil, fmt.Errorf("%s needs a discount between 0 and 1 exclusive, got %v", name, opts.Discount)
+		}
+		return NewDiscountedUCB(opts.Discount), nil
+	case StrategySlidingWindowUCB:
+		if opts.Window <= 0 {
+			return nil, fmt.Errorf("%s needs a positive window, got %v", name, opts.Window)
+		}
+		return NewSlidingWindowUCB(opts.Window), nil
+	case StrategyLinUCB:
+		if opts.LinUCBAlpha <= 0 {
+			return nil, fmt.Errorf("%s needs a positive alpha, got %v", name, opts.LinUCBAlpha)
+		}
+		return NewLinUCB(opts.LinUCBAlpha, opts.LinUCBDimension), nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", name)
+	}
+}
+
+// Strategies holds the strategy of every slot that overrides the default one.
+type Strategies struct {
+	fallback Strategy
+	slots    map[uuid.UUID]Strategy
+}
+
+func NewStrategies(fallback Strategy) *Strategies {
+	return &Strategies{
+		fallback: fallback,
+		slots:    make(map[uuid.UUID]Strategy),
+	}
+}
+
+func (s *Strategies) SetForSlot(slotID uuid.UUID, strategy Strategy) {
+	s.slots[slotID] = strategy
+}
+
+func (s *Strategies) ForSlot(slotID *uuid.UUID) Strategy {
+	if strategy, ok := s.slots[*slotID]; ok {
+		return strategy
+	}
+	return s.fallback
+}
@@ -0,0 +1,72 @@
+package mab
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var validOptions = Options{Epsilon: 0.1, EpsilonDecay: 1000, Discount: 0.99, Window: time.Hour, LinUCBAlpha: 0.5}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{
+		StrategyUCB1, StrategyUCB1Tuned, StrategyKLUCB, StrategyThompson, StrategyEpsilonGreedy, StrategyDecayingEpsilonGreedy,
+		StrategyDiscountedUCB, StrategySlidingWindowUCB, StrategyLinUCB,
+	} {
+		strategy, err := New(name, validOptions)
+		require.NoError(t, err)
+		require.Equal(t, name, NameOf(strategy))
+	}
+
+	require.Equal(t, "custom", NameOf(StrategyFunc(UCB1)))
+
+	_, err := New("unknown", Options{})
+	require.Error(t, err)
+
+	warmStart := validOptions
+	warmStart.Prior = Prior{Kind: PriorSlotAverage, Shows: 10}
+
+	strategy, err := New(StrategyThompson, warmStart)
+	require.NoError(t, err)
+	require.IsType(t, &WarmStart{}, strategy)
+	require.Equal(t, StrategyThompson, NameOf(strategy))
+
+	strategy, err = New(StrategyLinUCB, validOptions)
+	require.NoError(t, err)
+	require.IsType(t, &LinUCB{}, strategy)
+
+	_, err = New(StrategyLinUCB, warmStart)
+	require.Error(t, err, "linucb does not support a prior")
+
+	_, err = New(StrategyUCB1, Options{Prior: Prior{Kind: "pessimistic"}})
+	require.Error(t, err)
+
+	strategy, err = New(StrategyUCB1, Options{Prior: Prior{Kind: PriorNone, Shows: 20}})
+	require.NoError(t, err)
+	_, warmStarted := strategy.(*WarmStart)
+	require.False(t, warmStarted, "no prior does not warm-start")
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		strategy string
+		opts     Options
+	}{
+		"negative epsilon":      {StrategyEpsilonGreedy, Options{Epsilon: -0.1}},
+		"epsilon above one":     {StrategyDecayingEpsilonGreedy, Options{Epsilon: 1.5, EpsilonDecay: 1000}},
+		"zero epsilon decay":    {StrategyDecayingEpsilonGreedy, Options{Epsilon: 0.1}},
+		"negative decay":        {StrategyDecayingEpsilonGreedy, Options{Epsilon: 0.1, EpsilonDecay: -1}},
+		"zero discount":         {StrategyDiscountedUCB, Options{}},
+		"discount of one":       {StrategyDiscountedUCB, Options{Discount: 1}},
+		"zero window":           {StrategySlidingWindowUCB, Options{}},
+		"zero linucb alpha":     {StrategyLinUCB, Options{}},
+		"negative linucb alpha": {StrategyLinUCB, Options{LinUCBAlpha: -1}},
+		"linucb budget":         {StrategyLinUCB, Options{LinUCBAlpha: 0.5, Budget: Budget{Shows: 100, Share: 0.05}}},
+		"linucb conversions":    {StrategyLinUCB, Options{LinUCBAlpha: 0.5, Reward: RewardConversion}},
+		"prior below one click": {StrategyUCB1, Options{Prior: Prior{Kind: PriorOptimistic, Shows: 20, CTR: 0.01}}},
+		"prior ctr above one":   {StrategyUCB1, Options{Prior: Prior{Kind: PriorOptimistic, Shows: 20, CTR: 2}}},
+	} {
+		_, err := New(tc.strategy, tc.opts)
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,75 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// ThompsonSampling draws a click-through rate for every banner from its Beta(clicks+1, shows-clicks+1)
+// posterior and selects the banner with the highest draw.
+type ThompsonSampling struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func NewThompsonSampling(rnd *rand.Rand) *ThompsonSampling {
+	return &ThompsonSampling{rnd: rnd}
+}
+
+func (t *ThompsonSampling) Name() string {
+	return StrategyThompson
+}
+
+func (t *ThompsonSampling) Select(stats []*model.Stat) *model.Stat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var (
+		maxSample      = -1.0
+		rotationToShow *model.Stat
+	)
+
+	for _, stat := range stats {
+		failures := stat.Shows - stat.Clicks
+		if failures < 0 {
+			failures = 0
+		}
+
+		sample := betaSample(t.rnd, float64(stat.Clicks)+1, float64(failures)+1)
+		if sample > maxSample {
+			maxSample = sample
+			rotationToShow = stat
+		}
+	}
+	return rotationToShow
+}
+
+func betaSample(rnd *rand.Rand, alpha, beta float64) float64 {
+	x := gammaSample(rnd, alpha)
+	y := gammaSample(rnd, beta)
+	return x / (x + y)
+}
+
+// gammaSample draws from Gamma(shape, 1) with the Marsaglia-Tsang method.
+func gammaSample(rnd *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return gammaSample(rnd, shape+1) * math.Pow(rnd.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rnd.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rnd.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
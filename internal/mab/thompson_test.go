@@ -0,0 +1,61 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestThompsonSampling(t *testing.T) {
+	strategy := NewThompsonSampling(rand.New(rand.NewSource(1)))
+
+	stats := make([]*model.Stat, 0, 10)
+
+	for i := 1; i <= cap(stats); i++ {
+		stats = append(stats, &model.Stat{
+			BannerID: uuid.New(),
+			SlotID:   uuid.New(),
+			GroupID:  uuid.New(),
+		})
+	}
+
+	t.Run("unshown banners are explored", func(t *testing.T) {
+		for i := 1; i <= 200; i++ {
+			stat := strategy.Select(stats)
+			stat.Shows++
+		}
+
+		for _, stat := range stats {
+			require.NotEqual(t, 0, stat.Shows)
+		}
+	})
+
+	t.Run("the popular banner was shown more often than the others", func(t *testing.T) {
+		popularBannerID := uuid.New()
+		stats = append(stats, &model.Stat{
+			BannerID: popularBannerID,
+			SlotID:   uuid.New(),
+			GroupID:  uuid.New(),
+			Shows:    100,
+			Clicks:   10, //imitation of a high click-through rate
+		})
+
+		var (
+			maxShows              int
+			resultPopularBannerID uuid.UUID
+		)
+
+		for i := 1; i <= 200; i++ {
+			stat := strategy.Select(stats)
+			stat.Shows++
+
+			if stat.Shows > maxShows {
+				maxShows = stat.Shows
+				resultPopularBannerID = stat.BannerID
+			}
+		}
+		require.Equal(t, popularBannerID, resultPopularBannerID)
+	})
+}
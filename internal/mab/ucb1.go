@@ -16,14 +16,16 @@ func UCB1(stats []*model.Stat) *model.Stat {
 	for _, stat := range stats {
 		if stat.Shows == 0 {
 			return stat
-		} else {
-			totalShows += int64(stat.Shows)
-			avgIncome := float64(stat.Clicks) / float64(stat.Shows)
-			confidence := avgIncome + math.Sqrt(2*math.Log(float64(totalShows))/float64(stat.Shows))
-			if confidence >= maxConfidence {
-				maxConfidence = confidence
-				rotationToShow = stat
-			}
+		}
+		totalShows += int64(stat.Shows)
+	}
+
+	for _, stat := range stats {
+		avgIncome := float64(stat.Clicks) / float64(stat.Shows)
+		confidence := avgIncome + math.Sqrt(2*math.Log(float64(totalShows))/float64(stat.Shows))
+		if confidence >= maxConfidence {
+			maxConfidence = confidence
+			rotationToShow = stat
 		}
 	}
 	return rotationToShow
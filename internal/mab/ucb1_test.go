@@ -54,4 +54,15 @@ func TestUCB1(t *testing.T) {
 		}
 		require.Equal(t, popularBannerID, resultPopularBannerID)
 	})
+
+	t.Run("the selection does not depend on the order of stats", func(t *testing.T) {
+		stats := []*model.Stat{
+			{BannerID: uuid.New(), Shows: 10, Clicks: 1},
+			{BannerID: uuid.New(), Shows: 1000, Clicks: 150},
+			{BannerID: uuid.New(), Shows: 20, Clicks: 2},
+		}
+		reversed := []*model.Stat{stats[2], stats[1], stats[0]}
+
+		require.Equal(t, UCB1(stats).BannerID, UCB1(reversed).BannerID)
+	})
 }
@@ -0,0 +1,38 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math"
+)
+
+// UCB1Tuned is UCB1 with the exploration term bounded by the estimated variance of the
+// click-through rate, which is far below the worst case of 1/4 when the rate is small.
+func UCB1Tuned(stats []*model.Stat) *model.Stat {
+
+	var (
+		maxConfidence  float64
+		rotationToShow *model.Stat
+		totalShows     int64
+	)
+
+	for _, stat := range stats {
+		if stat.Shows == 0 {
+			return stat
+		}
+		totalShows += int64(stat.Shows)
+	}
+
+	logTotalShows := math.Log(float64(totalShows))
+
+	for _, stat := range stats {
+		shows := float64(stat.Shows)
+		avgIncome := math.Min(float64(stat.Clicks)/shows, 1)
+		variance := avgIncome*(1-avgIncome) + math.Sqrt(2*logTotalShows/shows)
+		confidence := avgIncome + math.Sqrt(logTotalShows/shows*math.Min(0.25, variance))
+		if confidence >= maxConfidence {
+			maxConfidence = confidence
+			rotationToShow = stat
+		}
+	}
+	return rotationToShow
+}
@@ -0,0 +1,191 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// PriorNone credits banners with nothing, like an empty Prior.Kind.
+	PriorNone = "none"
+	// PriorOptimistic credits every banner with Prior.Shows shows at Prior.CTR.
+	PriorOptimistic = "optimistic"
+	// PriorSlotAverage credits every banner with Prior.Shows shows at the average click-through rate of
+	// the slot, which is a Beta prior for Thompson sampling. Prior.CTR is used while the slot has no shows.
+	PriorSlotAverage = "slot-average"
+)
+
+// Prior is what a banner is assumed to have scored before its first show.
+type Prior struct {
+	// Kind is PriorOptimistic, PriorSlotAverage, or PriorNone or empty for no prior.
+	Kind string
+	// Shows is the weight of the prior. The more shows, the slower a new banner leaves it. Counts are whole,
+	// so a positive CTR needs Shows of at least 1/CTR to credit a click. Slot averages below that get
+	// the shows of one click instead.
+	Shows int
+	CTR   float64
+}
+
+func (p Prior) enabled() bool {
+	return p.Kind != "" && p.Kind != PriorNone
+}
+
+// Budget reserves a share of the selections for the banners that have fewer than Shows shows.
+type Budget struct {
+	Shows int
+	// Share is the probability that a selection goes to the least shown of these banners.
+	Share float64
+}
+
+// WarmStart ramps new banners up smoothly. Without it most strategies show a banner to every request
+// until it has been shown once, and then score it from a single show. WarmStart adds the prior to the
+// statistics the strategy scores, and spends the budget on banners that have not been shown enough.
+type WarmStart struct {
+	strategy Strategy
+	prior    Prior
+	budget   Budget
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// warmStartBuckets is a WarmStart of a BucketStrategy. The prior is added as a bucket of the current hour.
+type warmStartBuckets struct {
+	*WarmStart
+	strategy BucketStrategy
+}
+
+// NewWarmStart wraps the strategy. The result is a BucketStrategy if the strategy is one.
+func NewWarmStart(strategy Strategy, rnd *rand.Rand, prior Prior, budget Budget) Strategy {
+	warmStart := &WarmStart{
+		strategy: strategy,
+		prior:    prior,
+		budget:   budget,
+		rnd:      rnd,
+	}
+
+	if bucketStrategy, ok := strategy.(BucketStrategy); ok {
+		return &warmStartBuckets{WarmStart: warmStart, strategy: bucketStrategy}
+	}
+	return warmStart
+}
+
+// Name returns the name of the wrapped strategy.
+func (w *WarmStart) Name() string {
+	return NameOf(w.strategy)
+}
+
+func (w *WarmStart) Select(stats []*model.Stat) *model.Stat {
+	if stat := w.spendBudget(stats); stat != nil {
+		return stat
+	}
+
+	shows, clicks := w.priorCounts(stats)
+	if shows == 0 {
+		return w.strategy.Select(stats)
+	}
+
+	withPrior := func(stat *model.Stat) *model.Stat {
+		statWithPrior := *stat
+		statWithPrior.Shows += shows
+		statWithPrior.Clicks += clicks
+		return &statWithPrior
+	}
+
+	return selectFromCopies(stats, withPrior, w.strategy.Select)
+}
+
+func (w *warmStartBuckets) Since(now time.Time) time.Time {
+	return w.strategy.Since(now)
+}
+
+func (w *warmStartBuckets) SelectFromBuckets(stats []*model.Stat, buckets []*model.StatBucket, now time.Time) *model.Stat {
+	if stat := w.spendBudget(stats); stat != nil {
+		return stat
+	}
+
+	shows, clicks := w.priorCounts(stats)
+	if shows == 0 {
+		return w.strategy.SelectFromBuckets(stats, buckets, now)
+	}
+
+	withPrior := make([]*model.StatBucket, len(buckets), len(buckets)+len(stats))
+	copy(withPrior, buckets)
+	for _, stat := range stats {
+		withPrior = append(withPrior, &model.StatBucket{
+			BannerID: stat.BannerID,
+			SlotID:   stat.SlotID,
+			GroupID:  stat.GroupID,
+			Bucket:   now.Truncate(bucketDuration),
+			Shows:    shows,
+			Clicks:   clicks,
+		})
+	}
+
+	return w.strategy.SelectFromBuckets(stats, withPrior, now)
+}
+
+// spendBudget returns the least shown banner below the budget with the probability of the budget share,
+// and nil otherwise.
+func (w *WarmStart) spendBudget(stats []*model.Stat) *model.Stat {
+	if w.budget.Shows <= 0 || w.budget.Share <= 0 {
+		return nil
+	}
+
+	var leastShown *model.Stat
+	for _, stat := range stats {
+		if stat.Shows < w.budget.Shows && (leastShown == nil || stat.Shows < leastShown.Shows) {
+			leastShown = stat
+		}
+	}
+
+	if leastShown == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.rnd.Float64() >= w.budget.Share {
+		return nil
+	}
+	return leastShown
+}
+
+// priorCounts returns the shows and clicks every banner is credited with. A CTR too low for a whole
+// click within the prior shows is credited as one click over 1/CTR shows.
+func (w *WarmStart) priorCounts(stats []*model.Stat) (int, int) {
+	if w.prior.Shows <= 0 {
+		return 0, 0
+	}
+
+	ctr := w.prior.CTR
+
+	switch w.prior.Kind {
+	case PriorOptimistic:
+	case PriorSlotAverage:
+		var shows, clicks int
+		for _, stat := range stats {
+			shows += stat.Shows
+			clicks += stat.Clicks
+		}
+
+		if shows > 0 {
+			ctr = float64(clicks) / float64(shows)
+		}
+	default:
+		return 0, 0
+	}
+
+	ctr = math.Min(math.Max(ctr, 0), 1)
+	clicks := int(math.Round(ctr * float64(w.prior.Shows)))
+	if clicks == 0 && ctr > 0 {
+		// too few shows to credit a whole click, widen them instead of claiming a zero CTR
+		return int(math.Round(1 / ctr)), 1
+	}
+
+	return w.prior.Shows, clicks
+}
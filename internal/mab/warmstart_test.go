@@ -0,0 +1,96 @@
+package mab
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestWarmStart(t *testing.T) {
+	newStats := func() []*model.Stat {
+		return []*model.Stat{
+			{BannerID: uuid.New(), Shows: 2000, Clicks: 100},
+			{BannerID: uuid.New(), Shows: 2000, Clicks: 80},
+			{BannerID: uuid.New()},
+		}
+	}
+
+	// newShows counts how often the new banner, which is never clicked, is shown in the first rounds
+	newShows := func(strategy Strategy, stats []*model.Stat, rounds int) int {
+		for i := 0; i < rounds; i++ {
+			strategy.Select(stats).Shows++
+		}
+		return stats[2].Shows
+	}
+
+	t.Run("slot-average prior ramps a new banner up", func(t *testing.T) {
+		cold := newShows(NewThompsonSampling(rand.New(rand.NewSource(1))), newStats(), 10)
+
+		warm := newShows(NewWarmStart(NewThompsonSampling(rand.New(rand.NewSource(1))), rand.New(rand.NewSource(1)),
+			Prior{Kind: PriorSlotAverage, Shows: 200}, Budget{}), newStats(), 10)
+
+		// without a prior the new banner takes almost every request until its own shows outweigh the first one
+		require.GreaterOrEqual(t, cold, 8)
+		require.LessOrEqual(t, warm, 5)
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		first := StrategyFunc(func(stats []*model.Stat) *model.Stat {
+			return stats[0]
+		})
+		strategy := NewWarmStart(first, rand.New(rand.NewSource(1)), Prior{}, Budget{Shows: 10, Share: 0.2})
+
+		shows := newShows(strategy, newStats(), 20)
+		require.Greater(t, shows, 0)
+		require.Less(t, shows, 10)
+
+		require.Equal(t, 10, newShows(strategy, newStats(), 1000))
+	})
+
+	t.Run("prior counts", func(t *testing.T) {
+		for _, tc := range []struct {
+			prior         Prior
+			stats         []*model.Stat
+			shows, clicks int
+		}{
+			{prior: Prior{Kind: PriorOptimistic, Shows: 10, CTR: 1}, stats: newStats(), shows: 10, clicks: 10},
+			{prior: Prior{Kind: PriorSlotAverage, Shows: 100, CTR: 1}, stats: newStats(), shows: 100, clicks: 5},
+			{prior: Prior{Kind: PriorSlotAverage, Shows: 100, CTR: 0.1}, stats: newStats()[2:], shows: 100, clicks: 10},
+			{prior: Prior{Kind: PriorOptimistic, Shows: 100, CTR: 0.01}, stats: newStats(), shows: 100, clicks: 1},
+			{
+				prior: Prior{Kind: PriorSlotAverage, Shows: 20, CTR: 0.5},
+				stats: []*model.Stat{
+					{BannerID: uuid.New(), Shows: 1000, Clicks: 10},
+					{BannerID: uuid.New(), Shows: 1000, Clicks: 10},
+				},
+				shows:  100,
+				clicks: 1,
+			},
+		} {
+			warmStart := NewWarmStart(StrategyFunc(UCB1), nil, tc.prior, Budget{}).(*WarmStart)
+
+			shows, clicks := warmStart.priorCounts(tc.stats)
+			require.Equal(t, tc.shows, shows, "%+v", tc.prior)
+			require.Equal(t, tc.clicks, clicks, "%+v", tc.prior)
+		}
+	})
+
+	t.Run("bucket strategy", func(t *testing.T) {
+		stats := newStats()
+		strategy := NewWarmStart(NewDiscountedUCB(0.99), nil, Prior{Kind: PriorOptimistic, Shows: 10, CTR: 0.5}, Budget{})
+
+		bucketStrategy, ok := strategy.(BucketStrategy)
+		require.True(t, ok)
+		require.Equal(t, StrategyDiscountedUCB, NameOf(strategy))
+
+		now := time.Now()
+		buckets := []*model.StatBucket{
+			{BannerID: stats[0].BannerID, Bucket: now.Truncate(time.Hour), Shows: 2000, Clicks: 100},
+			{BannerID: stats[1].BannerID, Bucket: now.Truncate(time.Hour), Shows: 2000, Clicks: 80},
+		}
+		require.Equal(t, stats[2], bucketStrategy.SelectFromBuckets(stats, buckets, now))
+	})
+}
@@ -0,0 +1,81 @@
+// Package metrics holds the Prometheus metrics of the service.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+const namespace = "banner_rotation"
+
+var (
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_query_duration_seconds",
+		Help:      "Latency of the storage methods.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"method"})
+
+	Selections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "selections_total",
+		Help:      "Banners selected for a slot, by the strategy that selected them.",
+	}, []string{"slot_id", "banner_id", "strategy"})
+
+	Clicks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clicks_total",
+		Help:      "Clicks on banners in a slot.",
+	}, []string{"slot_id", "banner_id"})
+
+	Conversions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "conversions_total",
+		Help:      "Conversions attributed to banners in a slot.",
+	}, []string{"slot_id", "banner_id"})
+
+	Revenue = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "revenue_total",
+		Help:      "Value of the conversions attributed to banners in a slot.",
+	}, []string{"slot_id", "banner_id"})
+
+	// LateRewards are the clicks and conversions rejected because they arrived after the attribution window.
+	LateRewards = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "late_rewards_total",
+		Help:      "Clicks and conversions that arrived after the attribution window of their impression.",
+	}, []string{"slot_id", "type"})
+
+	// Decisions tells exploration, selecting a banner other than the one with the best click-through rate,
+	// from exploitation.
+	Decisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "strategy_decisions_total",
+		Help:      "Selections by strategy and whether they explored or exploited.",
+	}, []string{"strategy", "decision"})
+)
+
+// ObserveStorage starts timing a storage method. Call the returned function when it is done:
+//
+//	defer metrics.ObserveStorage("FindBannerByID")()
+func ObserveStorage(method string) func() {
+	start := time.Now()
+	return func() {
+		StorageDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
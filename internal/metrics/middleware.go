@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/routing"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Middleware times the requests of the route, labelled by the route pattern rather than the path.
+func Middleware(method, route string, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		start := time.Now()
+
+		next(w, req, params)
+
+		RequestDuration.WithLabelValues(method, route, strconv.Itoa(routing.Status(w))).Observe(time.Since(start).Seconds())
+	}
+}
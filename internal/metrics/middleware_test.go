@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/routing"
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	router := httprouter.New()
+	routing.NewRouter(router, Middleware).GET("/banners/:banner_id", func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for _, path := range []string{"/banners/1", "/banners/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// both requests are counted under the route, not under their paths
+	require.Equal(t, 1, testutil.CollectAndCount(RequestDuration))
+	require.Equal(t, 1, testutil.CollectAndCount(RequestDuration.MustCurryWith(map[string]string{
+		"method": http.MethodGet, "route": "/banners/:banner_id", "status": "404",
+	})))
+}
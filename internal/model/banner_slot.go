@@ -6,3 +6,14 @@ type BannerSlot struct {
 	BannerID uuid.UUID `json:"banner_id"`
 	SlotID   uuid.UUID `json:"slot_id"`
 }
+
+// BannerSlotResult tells whether a pair of a bulk link or unlink request was applied, and why not.
+type BannerSlotResult struct {
+	BannerID uuid.UUID `json:"banner_id"`
+	SlotID   uuid.UUID `json:"slot_id"`
+	Success  bool      `json:"success"`
+	// Err is why the pair failed. The handler turns it into Code and Error.
+	Err   error  `json:"-"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
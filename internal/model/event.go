@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	EventShow       = "show"
+	EventClick      = "click"
+	EventConversion = "conversion"
+)
+
+type Event struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	BannerID  uuid.UUID `json:"banner_id"`
+	SlotID    uuid.UUID `json:"slot_id"`
+	GroupID   uuid.UUID `json:"group_id"`
+	Timestamp time.Time `json:"timestamp"`
+	// Value is the monetary value of a conversion.
+	Value float64 `json:"value,omitempty"`
+	// ImpressionID is the show a click or conversion is attributed to, if it named one.
+	ImpressionID *uuid.UUID `json:"impression_id,omitempty"`
+	// Features are the ones a contextual strategy selected the banner with.
+	Features Features `json:"features,omitempty"`
+}
+
+func NewEvent(eventType string, stat *Stat) *Event {
+	return &Event{
+		ID:        uuid.New(),
+		Type:      eventType,
+		BannerID:  stat.BannerID,
+		SlotID:    stat.SlotID,
+		GroupID:   stat.GroupID,
+		Timestamp: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,4 @@
+package model
+
+// Features describe the request a banner is selected for, e.g. {"device": "mobile"}.
+type Features map[string]string
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Impression is a show of a banner. Its ID is the ID of the show event, and SelectBanner returns it
+// so that the clicks and conversions of the show can name it.
+type Impression struct {
+	ID       uuid.UUID `db:"id"`
+	BannerID uuid.UUID `db:"banner_id"`
+	SlotID   uuid.UUID `db:"slot_id"`
+	GroupID  uuid.UUID `db:"social_group_id"`
+	ShownAt  time.Time `db:"shown_at"`
+	// Features are the ones a contextual strategy selected the banner with, nil for other selections.
+	Features Features `db:"features"`
+	// ClickedAt and ConvertedAt are when the impression got its click and its conversion. An impression
+	// is rewarded with at most one of each, so replayed postbacks are not counted again.
+	ClickedAt   *time.Time `db:"clicked_at"`
+	ConvertedAt *time.Time `db:"converted_at"`
+}
+
+// Rewarded tells whether the impression already got the click or the conversion of the event type.
+func (i *Impression) Rewarded(eventType string) bool {
+	switch eventType {
+	case EventClick:
+		return i.ClickedAt != nil
+	case EventConversion:
+		return i.ConvertedAt != nil
+	default:
+		return false
+	}
+}
+
+// Reward records the click or conversion event on the impression.
+func (i *Impression) Reward(event *Event) {
+	rewardedAt := event.Timestamp
+
+	switch event.Type {
+	case EventClick:
+		i.ClickedAt = &rewardedAt
+	case EventConversion:
+		i.ConvertedAt = &rewardedAt
+	}
+}
+
+// ImpressionOf returns the impression recorded for a show event.
+func ImpressionOf(event *Event) *Impression {
+	return &Impression{
+		ID:       event.ID,
+		BannerID: event.BannerID,
+		SlotID:   event.SlotID,
+		GroupID:  event.GroupID,
+		ShownAt:  event.Timestamp,
+		Features: event.Features,
+	}
+}
+
+// Selection is the banner selected for a slot and the impression of its show.
+type Selection struct {
+	Banner     *Banner
+	Impression *Impression
+}
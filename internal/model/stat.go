@@ -8,4 +8,7 @@ type Stat struct {
 	GroupID  uuid.UUID `json:"group_id" db:"social_group_id"`
 	Shows    int       `json:"shows" db:"shows"`
 	Clicks   int       `json:"clicks" db:"clicks"`
+	// Conversions are attributed by postbacks, Revenue is the sum of their values.
+	Conversions int     `json:"conversions" db:"conversions"`
+	Revenue     float64 `json:"revenue" db:"revenue"`
 }
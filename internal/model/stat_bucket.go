@@ -0,0 +1,18 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type StatBucket struct {
+	BannerID uuid.UUID `json:"banner_id" db:"banner_id"`
+	SlotID   uuid.UUID `json:"slot_id" db:"slot_id"`
+	GroupID  uuid.UUID `json:"group_id" db:"social_group_id"`
+	Bucket   time.Time `json:"bucket" db:"bucket"`
+	Shows    int       `json:"shows" db:"shows"`
+	Clicks   int       `json:"clicks" db:"clicks"`
+
+	Conversions int     `json:"conversions" db:"conversions"`
+	Revenue     float64 `json:"revenue" db:"revenue"`
+}
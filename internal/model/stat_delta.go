@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StatDelta is the number of shows, clicks and conversions of a banner in a slot and social group
+// that are not yet written to the storage.
+type StatDelta struct {
+	BannerID uuid.UUID
+	SlotID   uuid.UUID
+	GroupID  uuid.UUID
+	// Bucket is the start of the hour the shows and clicks happened in.
+	Bucket time.Time
+	Shows  int
+	Clicks int
+
+	Conversions int
+	Revenue     float64
+}
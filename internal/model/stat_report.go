@@ -0,0 +1,32 @@
+package model
+
+import "github.com/google/uuid"
+
+const (
+	DimensionBanner = "banner"
+	DimensionSlot   = "slot"
+	DimensionGroup  = "group"
+)
+
+type StatFilter struct {
+	BannerID *uuid.UUID
+	SlotID   *uuid.UUID
+	GroupID  *uuid.UUID
+	// GroupBy lists the dimensions the statistics are aggregated by. Everything is summed up when it is empty.
+	GroupBy []string
+}
+
+type StatReport struct {
+	BannerID *uuid.UUID `json:"banner_id,omitempty" db:"banner_id"`
+	SlotID   *uuid.UUID `json:"slot_id,omitempty" db:"slot_id"`
+	GroupID  *uuid.UUID `json:"group_id,omitempty" db:"social_group_id"`
+	Shows    int64      `json:"shows" db:"shows"`
+	Clicks   int64      `json:"clicks" db:"clicks"`
+	CTR      float64    `json:"ctr" db:"-"`
+	// CTRLow and CTRHigh bound the 95% Wilson score interval of the click-through rate.
+	CTRLow  float64 `json:"ctr_low" db:"-"`
+	CTRHigh float64 `json:"ctr_high" db:"-"`
+
+	Conversions int64   `json:"conversions" db:"conversions"`
+	Revenue     float64 `json:"revenue" db:"revenue"`
+}
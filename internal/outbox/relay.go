@@ -0,0 +1,110 @@
+// Package outbox delivers the events that the storage recorded together with the statistics.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"log/slog"
+	"time"
+)
+
+// pruneInterval is how often the sent events older than the retention are deleted.
+const pruneInterval = time.Minute
+
+type storage interface {
+	RelayEvents(ctx context.Context, limit int, publish func(events []*model.Event) error) (int, error)
+	DeleteEventsSentBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+type publisher interface {
+	Publish(ctx context.Context, events ...*model.Event) error
+}
+
+// Relay publishes the events of the outbox at least once. Every event carries its outbox ID,
+// which consumers use to drop duplicates.
+type Relay struct {
+	storage   storage
+	publisher publisher
+	interval  time.Duration
+	batchSize int
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// NewRelay returns a relay that publishes up to batchSize events at a time every interval. Sent events
+// are deleted once they are older than retention, zero keeps them for ever.
+func NewRelay(storage storage, publisher publisher, interval time.Duration, batchSize int, retention time.Duration,
+	logger *slog.Logger) (*Relay, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("outbox interval must be positive, got %v", interval)
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("outbox batch size must be positive, got %d", batchSize)
+	}
+	if retention < 0 {
+		return nil, fmt.Errorf("outbox retention must not be negative, got %v", retention)
+	}
+
+	return &Relay{
+		storage:   storage,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		retention: retention,
+		logger:    logger,
+	}, nil
+}
+
+// Run relays the outbox every interval and prunes the sent events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.RelayAll(ctx); err != nil && ctx.Err() == nil {
+				r.logger.ErrorContext(ctx, "failed to relay outbox events", slog.Any("error", err))
+			}
+		case <-pruneTicker.C:
+			r.prune(ctx)
+		}
+	}
+}
+
+func (r *Relay) prune(ctx context.Context) {
+	if r.retention == 0 {
+		return
+	}
+
+	deleted, err := r.storage.DeleteEventsSentBefore(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.ErrorContext(ctx, "failed to prune outbox events", slog.Any("error", err))
+		}
+		return
+	}
+	r.logger.DebugContext(ctx, "outbox events pruned", slog.Int("deleted", deleted))
+}
+
+// RelayAll publishes batches of events until the outbox has no unsent events left.
+func (r *Relay) RelayAll(ctx context.Context) error {
+	for {
+		relayed, err := r.storage.RelayEvents(ctx, r.batchSize, func(events []*model.Event) error {
+			return r.publisher.Publish(ctx, events...)
+		})
+		if err != nil {
+			return err
+		}
+
+		if relayed < r.batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/aakosarev/banner-rotation/internal/event"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type outboxStorage struct {
+	mu     sync.Mutex
+	unsent []*model.Event
+}
+
+func (s *outboxStorage) RelayEvents(ctx context.Context, limit int, publish func(events []*model.Event) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := s.unsent
+	if len(batch) > limit {
+		batch = batch[:limit]
+	}
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	if err := publish(batch); err != nil {
+		return 0, err
+	}
+
+	s.unsent = s.unsent[len(batch):]
+	return len(batch), nil
+}
+
+func (s *outboxStorage) DeleteEventsSentBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(ctx context.Context, events ...*model.Event) error {
+	return errors.New("broker is down")
+}
+
+func TestRelay(t *testing.T) {
+	newStorage := func() *outboxStorage {
+		storage := &outboxStorage{}
+		for i := 0; i < 25; i++ {
+			storage.unsent = append(storage.unsent, model.NewEvent(model.EventShow, &model.Stat{BannerID: uuid.New()}))
+		}
+		return storage
+	}
+
+	t.Run("all events are published in batches", func(t *testing.T) {
+		storage := newStorage()
+		publisher := event.NewMemoryPublisher()
+
+		relay, err := NewRelay(storage, publisher, time.Second, 10, 0, logging.Discard())
+		require.NoError(t, err)
+
+		err = relay.RelayAll(context.Background())
+		require.NoError(t, err)
+
+		require.Len(t, publisher.Events(), 25)
+		require.Empty(t, storage.unsent)
+	})
+
+	t.Run("events stay in the outbox when publishing fails", func(t *testing.T) {
+		storage := newStorage()
+
+		relay, err := NewRelay(storage, failingPublisher{}, time.Second, 10, 0, logging.Discard())
+		require.NoError(t, err)
+
+		err = relay.RelayAll(context.Background())
+		require.Error(t, err)
+
+		require.Len(t, storage.unsent, 25)
+	})
+}
+
+func TestNewRelayRejectsInvalidOptions(t *testing.T) {
+	for _, tc := range []struct {
+		interval  time.Duration
+		batchSize int
+		retention time.Duration
+	}{
+		{interval: 0, batchSize: 100},
+		{interval: -time.Second, batchSize: 100},
+		{interval: time.Second, batchSize: 0},
+		{interval: time.Second, batchSize: 100, retention: -time.Hour},
+	} {
+		_, err := NewRelay(&outboxStorage{}, failingPublisher{}, tc.interval, tc.batchSize, tc.retention, logging.Discard())
+		require.Error(t, err, "%+v", tc)
+	}
+}
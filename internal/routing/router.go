@@ -0,0 +1,82 @@
+// Package routing registers routes on an httprouter.Router through a stack of middlewares, such as
+// the metrics, tracing and access log ones.
+package routing
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+// Middleware wraps the handle of a route. It gets the route pattern rather than the path, so IDs
+// do not end up in metric labels and span names.
+type Middleware func(method, route string, next httprouter.Handle) httprouter.Handle
+
+type Router struct {
+	router      *httprouter.Router
+	middlewares []Middleware
+}
+
+// NewRouter returns a router that registers the routes on router. The first middleware is the outermost.
+func NewRouter(router *httprouter.Router, middlewares ...Middleware) *Router {
+	return &Router{
+		router:      router,
+		middlewares: middlewares,
+	}
+}
+
+func (r *Router) GET(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodGet, path, handle)
+}
+
+func (r *Router) POST(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPost, path, handle)
+}
+
+func (r *Router) PUT(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPut, path, handle)
+}
+
+func (r *Router) DELETE(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodDelete, path, handle)
+}
+
+func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.Handle(method, path, func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		handler(w, req)
+	})
+}
+
+func (r *Router) Handle(method, path string, handle httprouter.Handle) {
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handle = r.middlewares[i](method, path, handle)
+	}
+
+	r.router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		handle(&statusRecorder{ResponseWriter: w, status: http.StatusOK}, req, params)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Status returns the status written to w so far, which middlewares read after calling the next handle.
+// It is 200 for writers that are not passed through a Router.
+func Status(w http.ResponseWriter) int {
+	recorder, ok := w.(*statusRecorder)
+	if !ok {
+		return http.StatusOK
+	}
+
+	return recorder.status
+}
@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	var calls []string
+
+	middleware := func(name string) Middleware {
+		return func(method, route string, next httprouter.Handle) httprouter.Handle {
+			return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+				calls = append(calls, name+" "+method+" "+route)
+				next(w, req, params)
+				calls = append(calls, name+" "+http.StatusText(Status(w)))
+			}
+		}
+	}
+
+	router := httprouter.New()
+	NewRouter(router, middleware("outer"), middleware("inner")).GET("/banners/:banner_id",
+		func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+			calls = append(calls, "handle "+params.ByName("banner_id"))
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/banners/1", nil))
+
+	require.Equal(t, []string{
+		"outer GET /banners/:banner_id",
+		"inner GET /banners/:banner_id",
+		"handle 1",
+		"inner Not Found",
+		"outer Not Found",
+	}, calls)
+
+	require.Equal(t, http.StatusOK, Status(httptest.NewRecorder()))
+}
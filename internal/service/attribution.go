@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/metrics"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"time"
+)
+
+// Attribution decides which clicks and conversions count. Both can arrive long after the show, and the
+// older the show, the less likely it is that the banner earned them.
+type Attribution struct {
+	// Window is how long after a show its clicks and conversions are attributed to it, zero for no limit.
+	Window time.Duration
+	// SlotWindows overrides Window for single slots.
+	SlotWindows map[uuid.UUID]time.Duration
+	// RequireImpression rejects the clicks and conversions that name a banner rather than an impression.
+	RequireImpression bool
+}
+
+func (a *Attribution) windowOf(slotID uuid.UUID) time.Duration {
+	if window, ok := a.SlotWindows[slotID]; ok {
+		return window
+	}
+	return a.Window
+}
+
+// SetAttribution replaces the attribution rules. Without them the clicks and conversions of
+// an impression are counted however late they are.
+func (s *Service) SetAttribution(attribution Attribution) {
+	s.attribution = attribution
+}
+
+// AddImpressionClick counts a click on the banner of the impression. Contextual strategies credit it
+// to the features the banner was selected with. An impression is clicked at most once.
+func (s *Service) AddImpressionClick(ctx context.Context, impressionID *uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.AddImpressionClick",
+		attribute.String("impression_id", impressionID.String()),
+		attribute.Bool("cached", s.cache != nil),
+	)
+
+	impression, err := s.attributedImpression(ctx, impressionID, model.EventClick)
+	if err == nil {
+		err = s.addClick(ctx, impression, &impression.BannerID, &impression.SlotID, &impression.GroupID)
+	}
+
+	tracing.End(span, err)
+	return err
+}
+
+// AddImpressionConversion attributes a conversion worth value to the banner of the impression. An impression
+// converts at most once.
+func (s *Service) AddImpressionConversion(ctx context.Context, impressionID *uuid.UUID, value float64) error {
+	ctx, span := tracing.Start(ctx, "Service.AddImpressionConversion",
+		attribute.String("impression_id", impressionID.String()),
+		attribute.Float64("value", value),
+		attribute.Bool("cached", s.cache != nil),
+	)
+
+	impression, err := s.attributedImpression(ctx, impressionID, model.EventConversion)
+	if err == nil {
+		err = s.addConversion(ctx, impression, &impression.BannerID, &impression.SlotID, &impression.GroupID, value)
+	}
+
+	tracing.End(span, err)
+	return err
+}
+
+// attributedImpression returns the impression a click or conversion names. The ones that arrive after
+// the attribution window of the slot are rejected, and logged and counted apart from the others. So are
+// the replays of a click or conversion the impression already has.
+func (s *Service) attributedImpression(ctx context.Context, impressionID *uuid.UUID, eventType string) (*model.Impression, error) {
+	var impression *model.Impression
+	if s.cache != nil {
+		impression = s.cache.impression(impressionID)
+	}
+
+	if impression == nil {
+		var err error
+		impression, err = s.storage.FindImpressionByID(ctx, impressionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if impression == nil {
+		return nil, errors.ErrImpressionNotFound
+	}
+
+	if impression.Rewarded(eventType) {
+		return nil, errors.ErrImpressionAlreadyRewarded
+	}
+
+	window := s.attribution.windowOf(impression.SlotID)
+	age := time.Since(impression.ShownAt)
+
+	if window > 0 && age > window {
+		metrics.LateRewards.WithLabelValues(impression.SlotID.String(), eventType).Inc()
+		s.logger.WarnContext(ctx, "reward outside attribution window",
+			slog.String("type", eventType),
+			slog.String("impression_id", impressionID.String()),
+			slog.String("slot_id", impression.SlotID.String()),
+			slog.String("banner_id", impression.BannerID.String()),
+			slog.Duration("age", age),
+			slog.Duration("window", window),
+		)
+		return nil, errors.ErrImpressionExpired
+	}
+
+	return impression, nil
+}
+
+// RunImpressionPruning deletes the impressions older than retention every interval until ctx is done.
+// Retention should be longer than every attribution window, the rewards of a deleted impression
+// are rejected as unknown rather than late.
+func (s *Service) RunImpressionPruning(ctx context.Context, interval, retention time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("impression prune interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			deleted, err := s.storage.DeleteImpressionsBefore(ctx, time.Now().Add(-retention))
+			if err != nil {
+				s.logger.ErrorContext(ctx, "failed to prune impressions", slog.Any("error", err))
+				continue
+			}
+			s.logger.DebugContext(ctx, "impressions pruned", slog.Int("deleted", deleted))
+		}
+	}
+}
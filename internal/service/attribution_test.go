@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/service"
+	"github.com/aakosarev/banner-rotation/internal/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAttribution(t *testing.T) {
+	for name, cached := range map[string]bool{"storage": false, "stats cache": true} {
+		cached := cached
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			rotationStorage := memory.NewStorage()
+			rotationService := newService(rotationStorage)
+			if cached {
+				rotationService.EnableStatsCache(time.Minute)
+			}
+			rotationService.SetAttribution(service.Attribution{Window: time.Hour, RequireImpression: true})
+
+			slot := &model.Slot{Description: "slot"}
+			socialGroup := &model.Group{Description: "social group"}
+			banner := &model.Banner{Description: "banner"}
+			require.NoError(t, rotationService.CreateSlot(ctx, slot))
+			require.NoError(t, rotationService.CreateSocialGroup(ctx, socialGroup))
+			require.NoError(t, rotationService.CreateBanner(ctx, banner))
+			require.NoError(t, rotationService.AddBannerToSlot(ctx, &banner.ID, &slot.ID))
+
+			selected, err := rotationService.SelectBanner(ctx, &slot.ID, &socialGroup.ID, nil)
+			require.NoError(t, err)
+			require.Equal(t, banner.ID, selected.Impression.BannerID)
+
+			require.ErrorIs(t, rotationService.AddClick(ctx, &banner.ID, &slot.ID, &socialGroup.ID), errors.ErrImpressionRequired)
+			require.ErrorIs(t, rotationService.AddConversion(ctx, &banner.ID, &slot.ID, &socialGroup.ID, 1), errors.ErrImpressionRequired)
+
+			missing := uuid.New()
+			require.ErrorIs(t, rotationService.AddImpressionClick(ctx, &missing), errors.ErrImpressionNotFound)
+
+			require.NoError(t, rotationService.AddImpressionClick(ctx, &selected.Impression.ID))
+			require.NoError(t, rotationService.AddImpressionConversion(ctx, &selected.Impression.ID, 3))
+
+			// replayed postbacks are not counted again
+			require.ErrorIs(t, rotationService.AddImpressionClick(ctx, &selected.Impression.ID), errors.ErrImpressionAlreadyRewarded)
+			require.ErrorIs(t, rotationService.AddImpressionConversion(ctx, &selected.Impression.ID, 3), errors.ErrImpressionAlreadyRewarded)
+
+			stat := &model.Stat{BannerID: banner.ID, SlotID: slot.ID, GroupID: socialGroup.ID}
+			old := model.NewEvent(model.EventShow, stat)
+			old.Timestamp = old.Timestamp.Add(-2 * time.Hour)
+			require.NoError(t, rotationStorage.AddShowToStat(ctx, stat, old))
+
+			require.ErrorIs(t, rotationService.AddImpressionClick(ctx, &old.ID), errors.ErrImpressionExpired)
+
+			rotationService.SetAttribution(service.Attribution{
+				Window:      time.Hour,
+				SlotWindows: map[uuid.UUID]time.Duration{slot.ID: 3 * time.Hour},
+			})
+			require.NoError(t, rotationService.AddImpressionClick(ctx, &old.ID))
+
+			require.NoError(t, rotationService.Flush(ctx))
+
+			// the flushed rewards are marked on the impressions
+			require.ErrorIs(t, rotationService.AddImpressionClick(ctx, &selected.Impression.ID), errors.ErrImpressionAlreadyRewarded)
+			require.ErrorIs(t, rotationService.AddImpressionClick(ctx, &old.ID), errors.ErrImpressionAlreadyRewarded)
+			require.NoError(t, rotationService.Flush(ctx))
+
+			reports, err := rotationService.GetStatReports(ctx, &model.StatFilter{SlotID: &slot.ID})
+			require.NoError(t, err)
+			require.Len(t, reports, 1)
+			require.Equal(t, int64(2), reports[0].Shows)
+			require.Equal(t, int64(2), reports[0].Clicks)
+			require.Equal(t, int64(1), reports[0].Conversions)
+			require.Equal(t, 3.0, reports[0].Revenue)
+		})
+	}
+}
+
+func TestRunImpressionPruningRejectsInvalidInterval(t *testing.T) {
+	rotationService := newService(memory.NewStorage())
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		require.Error(t, rotationService.RunImpressionPruning(context.Background(), interval, time.Hour))
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+)
+
+func (s *Service) CreateBanner(ctx context.Context, banner *model.Banner) error {
+	return s.storage.CreateBanner(ctx, banner)
+}
+
+func (s *Service) GetBanner(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error) {
+	banner, err := s.storage.FindBannerByID(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if banner == nil {
+		return nil, errors.ErrBannerNotFound
+	}
+
+	return banner, nil
+}
+
+func (s *Service) ListBanners(ctx context.Context) ([]*model.Banner, error) {
+	return s.storage.FindBanners(ctx)
+}
+
+func (s *Service) UpdateBanner(ctx context.Context, banner *model.Banner) error {
+	_, err := s.GetBanner(ctx, &banner.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.flushAndInvalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.UpdateBanner(ctx, banner)
+}
+
+func (s *Service) DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error {
+	_, err := s.GetBanner(ctx, bannerID)
+	if err != nil {
+		return err
+	}
+
+	err = s.flushAndInvalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.DeleteBanner(ctx, bannerID)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+)
+
+// maxBulkBannerSlots limits the pairs of one bulk request, so it does not hold the locks for too long.
+const maxBulkBannerSlots = 1000
+
+// AddBannersToSlots links every pair it can in one transaction and reports the result of every pair.
+func (s *Service) AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]*model.BannerSlotResult, error) {
+	if len(bannerSlots) > maxBulkBannerSlots {
+		return nil, errors.ErrTooManyBannerSlots
+	}
+
+	if len(bannerSlots) == 0 {
+		return []*model.BannerSlotResult{}, nil
+	}
+
+	pairErrors, err := s.storage.AddBannersToSlots(ctx, bannerSlots)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bannerSlotResults(bannerSlots, pairErrors), nil
+}
+
+// RemoveBannersFromSlots unlinks every pair it can in one transaction and reports the result of every pair.
+func (s *Service) RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]*model.BannerSlotResult, error) {
+	if len(bannerSlots) > maxBulkBannerSlots {
+		return nil, errors.ErrTooManyBannerSlots
+	}
+
+	if len(bannerSlots) == 0 {
+		return []*model.BannerSlotResult{}, nil
+	}
+
+	pairErrors, err := s.storage.RemoveBannersFromSlots(ctx, bannerSlots)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bannerSlotResults(bannerSlots, pairErrors), nil
+}
+
+// bannerSlotResults also invalidates the cached statistics of the slots that changed.
+func (s *Service) bannerSlotResults(bannerSlots []*model.BannerSlot, pairErrors []error) []*model.BannerSlotResult {
+	results := make([]*model.BannerSlotResult, len(bannerSlots))
+
+	for i, bannerSlot := range bannerSlots {
+		results[i] = &model.BannerSlotResult{
+			BannerID: bannerSlot.BannerID,
+			SlotID:   bannerSlot.SlotID,
+			Success:  pairErrors[i] == nil,
+		}
+
+		if pairErrors[i] != nil {
+			results[i].Err = pairErrors[i]
+			continue
+		}
+
+		s.invalidateSlot(&bannerSlot.SlotID)
+	}
+
+	return results
+}
@@ -0,0 +1,561 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	finalFlushTimeout = 10 * time.Second
+	// maxPendingEvents bounds the shows and clicks kept in memory while the storage fails.
+	maxPendingEvents = 100000
+)
+
+type slotGroupKey struct {
+	slotID        uuid.UUID
+	socialGroupID uuid.UUID
+}
+
+type rewardKey struct {
+	impressionID uuid.UUID
+	eventType    string
+}
+
+type deltaKey struct {
+	bannerID      uuid.UUID
+	slotID        uuid.UUID
+	socialGroupID uuid.UUID
+	bucket        time.Time
+}
+
+// statsCache keeps the statistics of the slots and social groups that had selections recently,
+// so selections are served from memory. Shows and clicks are counted in memory first
+// and written to the storage in batches by Service.Flush.
+type statsCache struct {
+	ttl time.Duration
+
+	// flushMu is held by Flush and read-held by reloads, so a reload never reads the storage while
+	// deltas are taken out of the cache but not written yet.
+	flushMu sync.RWMutex
+
+	mu      sync.Mutex
+	entries map[slotGroupKey]*cacheEntry
+	deltas  map[deltaKey]*model.StatDelta
+	events  []*model.Event
+	// impressions of the shows that are not flushed yet, the storage does not have them.
+	impressions map[uuid.UUID]*model.Impression
+	// rewards are the clicks and conversions of impressions that are not flushed yet, so that replays
+	// are rejected before the storage marks the impressions.
+	rewards map[rewardKey]bool
+}
+
+type cacheEntry struct {
+	mu       sync.Mutex
+	loadedAt time.Time
+	stats    []*model.Stat
+	banners  map[uuid.UUID]*model.Banner
+	// buckets are only loaded for slots with a mab.BucketStrategy.
+	buckets  []*model.StatBucket
+	bucketed bool
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{
+		ttl:         ttl,
+		entries:     make(map[slotGroupKey]*cacheEntry),
+		deltas:      make(map[deltaKey]*model.StatDelta),
+		impressions: make(map[uuid.UUID]*model.Impression),
+		rewards:     make(map[rewardKey]bool),
+	}
+}
+
+// EnableStatsCache makes the service serve selections and clicks from memory. Cached statistics
+// are reloaded from the storage once they are older than ttl.
+func (s *Service) EnableStatsCache(ttl time.Duration) {
+	s.cache = newStatsCache(ttl)
+}
+
+// RunStatsFlush flushes the cached shows and clicks every interval until ctx is done,
+// and once more before it returns.
+func (s *Service) RunStatsFlush(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("flush interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+			if err := s.Flush(flushCtx); err != nil {
+				s.logger.Error("failed to flush statistics on shutdown", slog.Any("error", err))
+			}
+			cancel()
+			return nil
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				s.logger.ErrorContext(ctx, "failed to flush statistics", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Flush writes the shows and clicks counted in memory to the storage. If the storage rejects them,
+// the ones of the banners, slots and social groups deleted meanwhile are dropped and the rest is written
+// again. What still fails is kept for the next flush, up to maxPendingEvents events. Reloads of the cached
+// statistics wait for it, so they neither miss the deltas being written nor count them twice.
+func (s *Service) Flush(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	s.cache.flushMu.Lock()
+	defer s.cache.flushMu.Unlock()
+
+	s.cache.mu.Lock()
+	deltas := make([]*model.StatDelta, 0, len(s.cache.deltas))
+	for _, delta := range s.cache.deltas {
+		deltas = append(deltas, delta)
+	}
+	events := s.cache.events
+	s.cache.deltas = make(map[deltaKey]*model.StatDelta)
+	s.cache.events = nil
+	s.cache.mu.Unlock()
+
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	err := s.storage.AddStatDeltas(ctx, deltas, events)
+	if err != nil {
+		var dropped []*model.Event
+		deltas, events, dropped = s.dropOrphanDeltas(ctx, deltas, events)
+		s.cache.forget(dropped)
+
+		err = nil
+		if len(deltas) != 0 {
+			err = s.storage.AddStatDeltas(ctx, deltas, events)
+		}
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	if err != nil {
+		if len(events)+len(s.cache.events) > maxPendingEvents {
+			s.logger.ErrorContext(ctx, "dropping statistics, too many are pending",
+				slog.Int("deltas", len(deltas)), slog.Int("events", len(events)))
+			s.cache.forgetLocked(events)
+			return err
+		}
+
+		for _, delta := range deltas {
+			s.cache.addDelta(delta)
+		}
+		s.cache.events = append(events, s.cache.events...)
+		return err
+	}
+
+	s.cache.forgetLocked(events)
+	return nil
+}
+
+// dropOrphanDeltas removes the deltas, and their events, of the banners, slots and social groups
+// that no longer exist, so they cannot fail every later flush. It removes nothing if the storage fails.
+func (s *Service) dropOrphanDeltas(ctx context.Context, deltas []*model.StatDelta,
+	events []*model.Event) ([]*model.StatDelta, []*model.Event, []*model.Event) {
+	orphans := make(map[deltaKey]bool)
+
+	for _, delta := range deltas {
+		exists, err := s.deltaTargetsExist(ctx, delta)
+		if err != nil {
+			return deltas, events, nil
+		}
+		if !exists {
+			orphans[keyOf(delta)] = true
+		}
+	}
+
+	if len(orphans) == 0 {
+		return deltas, events, nil
+	}
+
+	kept := deltas[:0]
+	for _, delta := range deltas {
+		if !orphans[keyOf(delta)] {
+			kept = append(kept, delta)
+		}
+	}
+
+	var keptEvents, dropped []*model.Event
+	for _, event := range events {
+		if orphans[eventKey(event)] {
+			dropped = append(dropped, event)
+		} else {
+			keptEvents = append(keptEvents, event)
+		}
+	}
+
+	s.logger.WarnContext(ctx, "dropping statistics of deleted banners, slots or social groups",
+		slog.Int("deltas", len(orphans)), slog.Int("events", len(dropped)))
+
+	return kept, keptEvents, dropped
+}
+
+func (s *Service) deltaTargetsExist(ctx context.Context, delta *model.StatDelta) (bool, error) {
+	banner, err := s.storage.FindBannerByID(ctx, &delta.BannerID)
+	if err != nil || banner == nil {
+		return false, err
+	}
+
+	slot, err := s.storage.FindSlotByID(ctx, &delta.SlotID)
+	if err != nil || slot == nil {
+		return false, err
+	}
+
+	socialGroup, err := s.storage.FindSocialGroupByID(ctx, &delta.GroupID)
+	if err != nil || socialGroup == nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func keyOf(delta *model.StatDelta) deltaKey {
+	return deltaKey{bannerID: delta.BannerID, slotID: delta.SlotID, socialGroupID: delta.GroupID, bucket: delta.Bucket}
+}
+
+func eventKey(event *model.Event) deltaKey {
+	return deltaKey{
+		bannerID:      event.BannerID,
+		slotID:        event.SlotID,
+		socialGroupID: event.GroupID,
+		bucket:        event.Timestamp.Truncate(time.Hour),
+	}
+}
+
+// forget drops the pending impressions and rewards of the events, which are written or will never be.
+func (c *statsCache) forget(events []*model.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.forgetLocked(events)
+}
+
+// forgetLocked must be called with c.mu held.
+func (c *statsCache) forgetLocked(events []*model.Event) {
+	for _, event := range events {
+		delete(c.impressions, event.ID)
+		if event.ImpressionID != nil {
+			delete(c.rewards, rewardKey{impressionID: *event.ImpressionID, eventType: event.Type})
+		}
+	}
+}
+
+// claimReward reports false if the impression of the click or conversion already has a pending one.
+func (c *statsCache) claimReward(event *model.Event) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := rewardKey{impressionID: *event.ImpressionID, eventType: event.Type}
+	if c.rewards[key] {
+		return false
+	}
+
+	c.rewards[key] = true
+	return true
+}
+
+func (c *statsCache) releaseReward(event *model.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.rewards, rewardKey{impressionID: *event.ImpressionID, eventType: event.Type})
+}
+
+// addDelta must be called with c.mu held.
+func (c *statsCache) addDelta(delta *model.StatDelta) {
+	key := keyOf(delta)
+
+	sum, ok := c.deltas[key]
+	if !ok {
+		sum = &model.StatDelta{
+			BannerID: delta.BannerID,
+			SlotID:   delta.SlotID,
+			GroupID:  delta.GroupID,
+			Bucket:   delta.Bucket,
+		}
+		c.deltas[key] = sum
+	}
+
+	sum.Shows += delta.Shows
+	sum.Clicks += delta.Clicks
+	sum.Conversions += delta.Conversions
+	sum.Revenue += delta.Revenue
+}
+
+func (c *statsCache) count(delta *model.StatDelta, event *model.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.addDelta(delta)
+	c.events = append(c.events, event)
+
+	if event.Type == model.EventShow {
+		c.impressions[event.ID] = model.ImpressionOf(event)
+	}
+}
+
+func (c *statsCache) impression(impressionID *uuid.UUID) *model.Impression {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.impressions[*impressionID]
+}
+
+// eventDelta returns an empty delta of the stat in the bucket of the event.
+func eventDelta(stat *model.Stat, event *model.Event) *model.StatDelta {
+	return &model.StatDelta{
+		BannerID: stat.BannerID,
+		SlotID:   stat.SlotID,
+		GroupID:  stat.GroupID,
+		Bucket:   event.Timestamp.Truncate(time.Hour),
+	}
+}
+
+func (s *Service) invalidateSlot(slotID *uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	for key := range s.cache.entries {
+		if key.slotID == *slotID {
+			delete(s.cache.entries, key)
+		}
+	}
+}
+
+// flushAndInvalidate writes the cached shows and clicks before banners, slots or social groups
+// change, so the flush does not fail on rows that are gone, and drops the cached statistics.
+func (s *Service) flushAndInvalidate(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	err := s.Flush(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	s.cache.entries = make(map[slotGroupKey]*cacheEntry)
+	return nil
+}
+
+func (e *cacheEntry) count(stat *model.Stat, delta *model.StatDelta) {
+	stat.Shows += delta.Shows
+	stat.Clicks += delta.Clicks
+	stat.Conversions += delta.Conversions
+	stat.Revenue += delta.Revenue
+
+	if !e.bucketed {
+		return
+	}
+
+	for _, statBucket := range e.buckets {
+		if statBucket.BannerID == stat.BannerID && statBucket.Bucket.Equal(delta.Bucket) {
+			statBucket.Shows += delta.Shows
+			statBucket.Clicks += delta.Clicks
+			statBucket.Conversions += delta.Conversions
+			statBucket.Revenue += delta.Revenue
+			return
+		}
+	}
+
+	e.buckets = append(e.buckets, &model.StatBucket{
+		BannerID:    stat.BannerID,
+		SlotID:      stat.SlotID,
+		GroupID:     stat.GroupID,
+		Bucket:      delta.Bucket,
+		Shows:       delta.Shows,
+		Clicks:      delta.Clicks,
+		Conversions: delta.Conversions,
+		Revenue:     delta.Revenue,
+	})
+}
+
+func (s *Service) cachedEntry(ctx context.Context, slotID, socialGroupID *uuid.UUID) (*cacheEntry, error) {
+	key := slotGroupKey{slotID: *slotID, socialGroupID: *socialGroupID}
+
+	s.cache.mu.Lock()
+	entry, ok := s.cache.entries[key]
+	s.cache.mu.Unlock()
+
+	if ok && time.Since(entry.loadedAt) < s.cache.ttl {
+		return entry, nil
+	}
+
+	s.cache.flushMu.RLock()
+	defer s.cache.flushMu.RUnlock()
+
+	entry, err := s.loadCacheEntry(ctx, slotID, socialGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	// the storage does not have the shows and clicks that are not flushed yet
+	for _, delta := range s.cache.deltas {
+		if delta.SlotID != *slotID || delta.GroupID != *socialGroupID {
+			continue
+		}
+		for _, stat := range entry.stats {
+			if stat.BannerID == delta.BannerID {
+				entry.count(stat, delta)
+			}
+		}
+	}
+
+	s.cache.entries[key] = entry
+	return entry, nil
+}
+
+func (s *Service) loadCacheEntry(ctx context.Context, slotID, socialGroupID *uuid.UUID) (*cacheEntry, error) {
+	err := s.checkSlotAndSocialGroupExists(ctx, slotID, socialGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.storage.FindStatsBySlotAndSocialGroup(ctx, slotID, socialGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	bannerIDs, err := s.storage.FindBannersInSlot(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cacheEntry{
+		loadedAt: time.Now(),
+		banners:  make(map[uuid.UUID]*model.Banner, len(bannerIDs)),
+	}
+
+	for _, stat := range linkStats(stats, bannerIDs, slotID, socialGroupID) {
+		banner, err := s.storage.FindBannerByID(ctx, &stat.BannerID)
+		if err != nil {
+			return nil, err
+		}
+
+		if banner != nil {
+			entry.banners[banner.ID] = banner
+			entry.stats = append(entry.stats, stat)
+		}
+	}
+
+	if bucketStrategy, ok := s.strategies.ForSlot(slotID).(mab.BucketStrategy); ok {
+		entry.bucketed = true
+		entry.buckets, err = s.storage.FindStatBucketsBySlotAndSocialGroup(ctx, slotID, socialGroupID, bucketStrategy.Since(entry.loadedAt))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+func (s *Service) selectCachedBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID, features model.Features) (*model.Selection, error) {
+	entry, err := s.cachedEntry(ctx, slotID, socialGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entry.stats) == 0 {
+		return nil, errors.ErrNoOneBannerFoundForSlot
+	}
+
+	strategy := s.strategies.ForSlot(slotID)
+	contextFeatures := selectionFeatures(strategy, socialGroupID, features)
+
+	entry.mu.Lock()
+	selectedStat := chooseStat(strategy, entry.stats, entry.buckets, contextFeatures)
+	s.observeSelection(ctx, strategy, entry.stats, selectedStat)
+	event := model.NewEvent(model.EventShow, selectedStat)
+	event.Features = contextFeatures
+	delta := eventDelta(selectedStat, event)
+	delta.Shows = 1
+	entry.count(selectedStat, delta)
+	entry.mu.Unlock()
+
+	s.cache.count(delta, event)
+	observeShow(strategy, selectedStat, contextFeatures)
+
+	return &model.Selection{Banner: entry.banners[selectedStat.BannerID], Impression: model.ImpressionOf(event)}, nil
+}
+
+// addCachedEvent counts the click or conversion in memory. It reports false if the banner is not linked
+// to the slot, so the event has to be written to the storage directly. It fails with
+// ErrImpressionAlreadyRewarded if the impression of the event has a pending one.
+func (s *Service) addCachedEvent(ctx context.Context, event *model.Event) (bool, error) {
+	entry, err := s.cachedEntry(ctx, &event.SlotID, &event.GroupID)
+	if err != nil {
+		return false, err
+	}
+
+	if event.ImpressionID != nil && !s.cache.claimReward(event) {
+		return false, errors.ErrImpressionAlreadyRewarded
+	}
+
+	var (
+		eventStat *model.Stat
+		delta     *model.StatDelta
+	)
+
+	entry.mu.Lock()
+	for _, stat := range entry.stats {
+		if stat.BannerID == event.BannerID {
+			eventStat = stat
+			delta = eventDelta(stat, event)
+			if event.Type == model.EventConversion {
+				delta.Conversions = 1
+				delta.Revenue = event.Value
+			} else {
+				delta.Clicks = 1
+			}
+			entry.count(stat, delta)
+			break
+		}
+	}
+	entry.mu.Unlock()
+
+	if eventStat == nil {
+		if event.ImpressionID != nil {
+			s.cache.releaseReward(event)
+		}
+		return false, nil
+	}
+
+	s.cache.count(delta, event)
+
+	if event.Type == model.EventClick {
+		s.observeClick(eventStat, event)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/logging"
+	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/service"
+	"github.com/aakosarev/banner-rotation/internal/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlushDropsStatsOfDeletedBanners(t *testing.T) {
+	ctx := context.Background()
+	rotationStorage := memory.NewStorage()
+	rotationService := newService(rotationStorage)
+	rotationService.EnableStatsCache(time.Minute)
+
+	slot := &model.Slot{Description: "slot"}
+	socialGroup := &model.Group{Description: "social group"}
+	require.NoError(t, rotationService.CreateSlot(ctx, slot))
+	require.NoError(t, rotationService.CreateSocialGroup(ctx, socialGroup))
+
+	banners := make([]*model.Banner, 2)
+	for i := range banners {
+		banners[i] = &model.Banner{Description: "banner"}
+		require.NoError(t, rotationService.CreateBanner(ctx, banners[i]))
+		require.NoError(t, rotationService.AddBannerToSlot(ctx, &banners[i].ID, &slot.ID))
+	}
+
+	shows := make(map[uuid.UUID]int)
+	for i := 0; i < 10; i++ {
+		selected, err := rotationService.SelectBanner(ctx, &slot.ID, &socialGroup.ID, nil)
+		require.NoError(t, err)
+		shows[selected.Banner.ID]++
+	}
+	require.Len(t, shows, 2, "UCB1 shows every banner")
+
+	// another instance deletes the banner, its cached shows can never be written
+	require.NoError(t, rotationStorage.RemoveBannerFromSlot(ctx, &banners[0].ID, &slot.ID))
+	require.NoError(t, rotationStorage.DeleteBanner(ctx, &banners[0].ID))
+
+	require.NoError(t, rotationService.Flush(ctx))
+
+	stat, err := rotationStorage.FindStatByParams(ctx, &banners[1].ID, &slot.ID, &socialGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, shows[banners[1].ID], stat.Shows)
+
+	_, err = rotationService.SelectBanner(ctx, &slot.ID, &socialGroup.ID, nil)
+	require.NoError(t, err)
+	require.NoError(t, rotationService.Flush(ctx), "later flushes are not blocked")
+}
+
+func TestRunStatsFlushRejectsInvalidInterval(t *testing.T) {
+	rotationService := newService(memory.NewStorage())
+	rotationService.EnableStatsCache(time.Minute)
+
+	require.Error(t, rotationService.RunStatsFlush(context.Background(), 0))
+}
+
+// slowFlushStorage blocks the first flush until release is closed.
+type slowFlushStorage struct {
+	*memory.Storage
+	flushing chan struct{}
+	release  chan struct{}
+}
+
+func (s *slowFlushStorage) AddStatDeltas(ctx context.Context, deltas []*model.StatDelta, events []*model.Event) error {
+	select {
+	case <-s.flushing:
+	default:
+		close(s.flushing)
+		<-s.release
+	}
+	return s.Storage.AddStatDeltas(ctx, deltas, events)
+}
+
+func TestReloadDuringFlushKeepsPendingStats(t *testing.T) {
+	ctx := context.Background()
+	rotationStorage := &slowFlushStorage{
+		Storage:  memory.NewStorage(),
+		flushing: make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+
+	var seenShows atomic.Int64
+	strategy := mab.StrategyFunc(func(stats []*model.Stat) *model.Stat {
+		seenShows.Store(int64(stats[0].Shows))
+		return stats[0]
+	})
+
+	rotationService := service.NewService(rotationStorage, mab.NewStrategies(strategy), logging.Discard())
+	// every selection reloads the statistics
+	rotationService.EnableStatsCache(time.Nanosecond)
+
+	slot := &model.Slot{Description: "slot"}
+	socialGroup := &model.Group{Description: "social group"}
+	banner := &model.Banner{Description: "banner"}
+	require.NoError(t, rotationService.CreateSlot(ctx, slot))
+	require.NoError(t, rotationService.CreateSocialGroup(ctx, socialGroup))
+	require.NoError(t, rotationService.CreateBanner(ctx, banner))
+	require.NoError(t, rotationService.AddBannerToSlot(ctx, &banner.ID, &slot.ID))
+
+	for i := 0; i < 3; i++ {
+		_, err := rotationService.SelectBanner(ctx, &slot.ID, &socialGroup.ID, nil)
+		require.NoError(t, err)
+	}
+
+	flushed := make(chan error)
+	go func() {
+		flushed <- rotationService.Flush(ctx)
+	}()
+	<-rotationStorage.flushing
+
+	selected := make(chan error)
+	go func() {
+		_, err := rotationService.SelectBanner(ctx, &slot.ID, &socialGroup.ID, nil)
+		selected <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(rotationStorage.release)
+
+	require.NoError(t, <-flushed)
+	require.NoError(t, <-selected)
+	require.Equal(t, int64(3), seenShows.Load(), "the reload sees the shows being flushed")
+}
@@ -4,35 +4,84 @@ import (
 	"context"
 	"github.com/aakosarev/banner-rotation/internal/errors"
 	"github.com/aakosarev/banner-rotation/internal/mab"
+	"github.com/aakosarev/banner-rotation/internal/metrics"
 	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"strconv"
 	"sync"
+	"time"
 )
 
 type storage interface {
+	Ping(ctx context.Context) error
 	AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
 	FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUID) (*model.BannerSlot, error)
 	RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
-	FindStatByParams(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) (*model.Stat, error)
-	CreateStat(ctx context.Context, stat *model.Stat) error
-	AddClickToStat(ctx context.Context, stat *model.Stat) error
-	AddShowToStat(ctx context.Context, stat *model.Stat) error
+	AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error)
+	RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error)
+	AddClickToStat(ctx context.Context, stat *model.Stat, event *model.Event) error
+	AddConversionToStat(ctx context.Context, stat *model.Stat, event *model.Event) error
+	AddShowToStat(ctx context.Context, stat *model.Stat, event *model.Event) error
+	AddStatDeltas(ctx context.Context, deltas []*model.StatDelta, events []*model.Event) error
 	FindStatsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID) ([]*model.Stat, error)
+	FindStatBucketsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID, since time.Time) ([]*model.StatBucket, error)
 	FindBannersInSlot(ctx context.Context, slotID *uuid.UUID) ([]*uuid.UUID, error)
 	FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error)
 	FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error)
 	FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error)
+	FindStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error)
+	FindImpressionByID(ctx context.Context, impressionID *uuid.UUID) (*model.Impression, error)
+	DeleteImpressionsBefore(ctx context.Context, before time.Time) (int, error)
+
+	CreateBanner(ctx context.Context, banner *model.Banner) error
+	FindBanners(ctx context.Context) ([]*model.Banner, error)
+	UpdateBanner(ctx context.Context, banner *model.Banner) error
+	DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error
+
+	CreateSlot(ctx context.Context, slot *model.Slot) error
+	FindSlots(ctx context.Context) ([]*model.Slot, error)
+	UpdateSlot(ctx context.Context, slot *model.Slot) error
+	DeleteSlot(ctx context.Context, slotID *uuid.UUID) error
+
+	CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error
+	FindSocialGroups(ctx context.Context) ([]*model.Group, error)
+	UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error
+	DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error
+}
+
+type strategies interface {
+	ForSlot(slotID *uuid.UUID) mab.Strategy
 }
 
 type Service struct {
-	storage storage
+	storage     storage
+	strategies  strategies
+	logger      *slog.Logger
+	cache       *statsCache
+	attribution Attribution
+}
+
+func NewService(storage storage, strategies strategies, logger *slog.Logger) *Service {
+	return &Service{
+		storage:    storage,
+		strategies: strategies,
+		logger:     logger,
+	}
 }
 
-func NewService(storage storage) *Service {
-	return &Service{storage: storage}
+// Ping checks that the storage can be reached.
+func (s *Service) Ping(ctx context.Context) error {
+	return s.storage.Ping(ctx)
 }
 
 func (s *Service) checkBannerAndSlotExists(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	// the lookups run in parallel, so their spans overlap under this one
+	ctx, span := tracing.Start(ctx, "Service.checkBannerAndSlotExists")
+	defer span.End()
+
 	wg := sync.WaitGroup{}
 	wg.Add(2)
 
@@ -99,7 +148,13 @@ func (s *Service) AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UU
 		return errors.ErrBannerAlreadyLinkedToSlot
 	}
 
-	return s.storage.AddBannerToSlot(ctx, bannerID, slotID)
+	err = s.storage.AddBannerToSlot(ctx, bannerID, slotID)
+	if err != nil {
+		return err
+	}
+
+	s.invalidateSlot(slotID)
+	return nil
 }
 
 func (s *Service) RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
@@ -108,10 +163,19 @@ func (s *Service) RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uu
 		return err
 	}
 
-	return s.storage.RemoveBannerFromSlot(ctx, bannerID, slotID)
+	err = s.storage.RemoveBannerFromSlot(ctx, bannerID, slotID)
+	if err != nil {
+		return err
+	}
+
+	s.invalidateSlot(slotID)
+	return nil
 }
 
 func (s *Service) checkSlotAndSocialGroupExists(ctx context.Context, slotID, socialGroupID *uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.checkSlotAndSocialGroupExists")
+	defer span.End()
+
 	wg := sync.WaitGroup{}
 	wg.Add(2)
 
@@ -163,7 +227,30 @@ func (s *Service) checkSlotAndSocialGroupExists(ctx context.Context, slotID, soc
 	return nil
 }
 
-func (s *Service) SelectBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID) (*model.Banner, error) {
+func (s *Service) SelectBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID, features model.Features) (*model.Selection, error) {
+	ctx, span := tracing.Start(ctx, "Service.SelectBanner",
+		attribute.String("slot_id", slotID.String()),
+		attribute.String("social_group_id", socialGroupID.String()),
+		attribute.Bool("cached", s.cache != nil),
+	)
+
+	selection, err := s.selectBanner(ctx, slotID, socialGroupID, features)
+	if selection != nil {
+		span.SetAttributes(
+			attribute.String("banner_id", selection.Banner.ID.String()),
+			attribute.String("impression_id", selection.Impression.ID.String()),
+		)
+	}
+
+	tracing.End(span, err)
+	return selection, err
+}
+
+func (s *Service) selectBanner(ctx context.Context, slotID, socialGroupID *uuid.UUID, features model.Features) (*model.Selection, error) {
+	if s.cache != nil {
+		return s.selectCachedBanner(ctx, slotID, socialGroupID, features)
+	}
+
 	err := s.checkSlotAndSocialGroupExists(ctx, slotID, socialGroupID)
 	if err != nil {
 		return nil, err
@@ -183,6 +270,69 @@ func (s *Service) SelectBanner(ctx context.Context, slotID, socialGroupID *uuid.
 		return nil, errors.ErrNoOneBannerFoundForSlot
 	}
 
+	statsWithLink := linkStats(stats, bannerIDs, slotID, socialGroupID)
+
+	strategy := s.strategies.ForSlot(slotID)
+	contextFeatures := selectionFeatures(strategy, socialGroupID, features)
+
+	selectedStat, err := s.selectStat(ctx, strategy, slotID, socialGroupID, statsWithLink, contextFeatures)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedBanner, err := s.storage.FindBannerByID(ctx, &selectedStat.BannerID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := model.NewEvent(model.EventShow, selectedStat)
+	event.Features = contextFeatures
+
+	err = s.storage.AddShowToStat(ctx, selectedStat, event)
+	if err != nil {
+		return nil, err
+	}
+
+	s.observeSelection(ctx, strategy, statsWithLink, selectedStat)
+	observeShow(strategy, selectedStat, contextFeatures)
+
+	return &model.Selection{Banner: selectedBanner, Impression: model.ImpressionOf(event)}, nil
+}
+
+// observeSelection counts the selection in the metrics and logs the stats it was made from at the debug
+// level. The stats must not count its show yet.
+func (s *Service) observeSelection(ctx context.Context, strategy mab.Strategy, stats []*model.Stat, selectedStat *model.Stat) {
+	name := mab.NameOf(strategy)
+	metrics.Selections.WithLabelValues(selectedStat.SlotID.String(), selectedStat.BannerID.String(), name).Inc()
+
+	decision := "exploit"
+	if mab.IsExploration(strategy, stats, selectedStat) {
+		decision = "explore"
+	}
+	metrics.Decisions.WithLabelValues(name, decision).Inc()
+
+	if !s.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	candidates := make([]model.Stat, len(stats))
+	for i, stat := range stats {
+		candidates[i] = *stat
+	}
+
+	s.logger.DebugContext(ctx, "banner selected",
+		slog.String("slot_id", selectedStat.SlotID.String()),
+		slog.String("social_group_id", selectedStat.GroupID.String()),
+		slog.String("banner_id", selectedStat.BannerID.String()),
+		slog.String("strategy", name),
+		slog.String("decision", decision),
+		slog.Any("candidates", candidates),
+	)
+}
+
+// linkStats returns the stats of every banner in the slot, with zero shows and clicks for the banners
+// that have no stats in the social group yet.
+func linkStats(stats []*model.Stat, bannerIDs []*uuid.UUID, slotID, socialGroupID *uuid.UUID) []*model.Stat {
 	var (
 		statsWithLink []*model.Stat
 	)
@@ -205,33 +355,70 @@ nextBanner:
 		})
 	}
 
-	selectedStat := mab.UCB1(statsWithLink)
-
-	selectedBanner, err := s.storage.FindBannerByID(ctx, &selectedStat.BannerID)
-	if err != nil {
-		return nil, err
-	}
+	return statsWithLink
+}
 
-	bdStat, err := s.storage.FindStatByParams(ctx, &selectedStat.BannerID, &selectedStat.SlotID, &selectedStat.GroupID)
-	if err != nil {
-		return nil, err
-	}
-	if bdStat == nil {
-		err = s.storage.CreateStat(ctx, selectedStat)
+func (s *Service) selectStat(ctx context.Context, strategy mab.Strategy, slotID, socialGroupID *uuid.UUID, stats []*model.Stat,
+	contextFeatures model.Features) (*model.Stat, error) {
+	var buckets []*model.StatBucket
+	if bucketStrategy, ok := strategy.(mab.BucketStrategy); ok && contextFeatures == nil {
+		var err error
+		buckets, err = s.storage.FindStatBucketsBySlotAndSocialGroup(ctx, slotID, socialGroupID, bucketStrategy.Since(time.Now()))
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	err = s.storage.AddShowToStat(ctx, selectedStat)
-	if err != nil {
-		return nil, err
+	return chooseStat(strategy, stats, buckets, contextFeatures), nil
+}
+
+// selectionFeatures returns the features a contextual strategy selects with, and nil if the strategy
+// is not contextual or the request has no features, so the selection falls back to the statistics.
+func selectionFeatures(strategy mab.Strategy, socialGroupID *uuid.UUID, features model.Features) model.Features {
+	if _, ok := strategy.(mab.ContextualStrategy); !ok || len(features) == 0 {
+		return nil
 	}
+	return requestFeatures(socialGroupID, features)
+}
 
-	return selectedBanner, nil
+// chooseStat selects the stat with the most specific method the strategy supports.
+func chooseStat(strategy mab.Strategy, stats []*model.Stat, buckets []*model.StatBucket, contextFeatures model.Features) *model.Stat {
+	if contextFeatures != nil {
+		return strategy.(mab.ContextualStrategy).SelectWithFeatures(stats, contextFeatures)
+	}
+
+	if bucketStrategy, ok := strategy.(mab.BucketStrategy); ok {
+		return bucketStrategy.SelectFromBuckets(stats, buckets, time.Now())
+	}
+
+	return strategy.Select(stats)
+}
+
+// observeShow records the show of a contextual selection. The shows of the selections that fell back
+// to the statistics are not observed, their clicks are not either.
+func observeShow(strategy mab.Strategy, stat *model.Stat, contextFeatures model.Features) {
+	if contextFeatures == nil {
+		return
+	}
+	strategy.(mab.ContextualStrategy).ObserveShow(stat, contextFeatures)
+}
+
+// requestFeatures adds the social group and the hour of day to the features sent with the request.
+func requestFeatures(socialGroupID *uuid.UUID, features model.Features) model.Features {
+	result := model.Features{
+		"group": socialGroupID.String(),
+		"hour":  strconv.Itoa(time.Now().Hour()),
+	}
+	for key, value := range features {
+		result[key] = value
+	}
+	return result
 }
 
 func (s *Service) checkBannerAndSlotAndSocialGroupExists(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.checkBannerAndSlotAndSocialGroupExists")
+	defer span.End()
+
 	wg := sync.WaitGroup{}
 	wg.Add(3)
 
@@ -304,31 +491,142 @@ func (s *Service) checkBannerAndSlotAndSocialGroupExists(ctx context.Context, ba
 }
 
 func (s *Service) AddClick(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.AddClick",
+		attribute.String("banner_id", bannerID.String()),
+		attribute.String("slot_id", slotID.String()),
+		attribute.String("social_group_id", socialGroupID.String()),
+		attribute.Bool("cached", s.cache != nil),
+	)
+
+	err := s.addClick(ctx, nil, bannerID, slotID, socialGroupID)
+
+	tracing.End(span, err)
+	return err
+}
+
+// addClick counts the click. impression is nil if the click does not name one. Contextual strategies only
+// learn from the clicks on impressions, the features of other clicks may differ from the ones of the show.
+func (s *Service) addClick(ctx context.Context, impression *model.Impression, bannerID, slotID, socialGroupID *uuid.UUID) error {
+	if impression == nil && s.attribution.RequireImpression {
+		return errors.ErrImpressionRequired
+	}
+
+	stat := &model.Stat{
+		BannerID: *bannerID,
+		SlotID:   *slotID,
+		GroupID:  *socialGroupID,
+	}
+
+	event := model.NewEvent(model.EventClick, stat)
+	if impression != nil {
+		event.ImpressionID = &impression.ID
+		event.Features = impression.Features
+	}
+
+	if s.cache != nil {
+		counted, err := s.addCachedEvent(ctx, event)
+		if err != nil {
+			return err
+		}
+
+		if counted {
+			metrics.Clicks.WithLabelValues(slotID.String(), bannerID.String()).Inc()
+			return nil
+		}
+	}
 
 	err := s.checkBannerAndSlotAndSocialGroupExists(ctx, bannerID, slotID, socialGroupID)
 	if err != nil {
 		return err
 	}
 
-	stat, err := s.storage.FindStatByParams(ctx, bannerID, slotID, socialGroupID)
+	err = s.storage.AddClickToStat(ctx, stat, event)
 	if err != nil {
 		return err
 	}
 
-	if stat == nil {
-		err = s.storage.CreateStat(ctx, &model.Stat{
-			BannerID: *bannerID,
-			SlotID:   *slotID,
-			GroupID:  *socialGroupID,
-			Shows:    0,
-			Clicks:   1,
-		})
+	metrics.Clicks.WithLabelValues(slotID.String(), bannerID.String()).Inc()
+	s.observeClick(stat, event)
+
+	return nil
+}
+
+// observeClick credits the click to the features the banner was selected with.
+func (s *Service) observeClick(stat *model.Stat, event *model.Event) {
+	if event.Features == nil {
+		return
+	}
+
+	if contextual, ok := s.strategies.ForSlot(&stat.SlotID).(mab.ContextualStrategy); ok {
+		contextual.ObserveClick(stat, event.Features)
+	}
+}
+
+// AddConversion attributes a conversion worth value to the banner shown in the slot to the social group.
+func (s *Service) AddConversion(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID, value float64) error {
+	ctx, span := tracing.Start(ctx, "Service.AddConversion",
+		attribute.String("banner_id", bannerID.String()),
+		attribute.String("slot_id", slotID.String()),
+		attribute.String("social_group_id", socialGroupID.String()),
+		attribute.Float64("value", value),
+		attribute.Bool("cached", s.cache != nil),
+	)
+
+	err := s.addConversion(ctx, nil, bannerID, slotID, socialGroupID, value)
+
+	tracing.End(span, err)
+	return err
+}
+
+// addConversion counts the conversion. impression is nil if the conversion does not name one.
+func (s *Service) addConversion(ctx context.Context, impression *model.Impression, bannerID, slotID, socialGroupID *uuid.UUID, value float64) error {
+	if value < 0 {
+		return errors.ErrNegativeConversionValue
+	}
+
+	if impression == nil && s.attribution.RequireImpression {
+		return errors.ErrImpressionRequired
+	}
+
+	stat := &model.Stat{
+		BannerID: *bannerID,
+		SlotID:   *slotID,
+		GroupID:  *socialGroupID,
+	}
+
+	event := model.NewEvent(model.EventConversion, stat)
+	event.Value = value
+	if impression != nil {
+		event.ImpressionID = &impression.ID
+	}
+
+	if s.cache != nil {
+		counted, err := s.addCachedEvent(ctx, event)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		if counted {
+			observeConversion(bannerID, slotID, value)
+			return nil
+		}
 	}
 
-	return s.storage.AddClickToStat(ctx, stat)
+	err := s.checkBannerAndSlotAndSocialGroupExists(ctx, bannerID, slotID, socialGroupID)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.AddConversionToStat(ctx, stat, event)
+	if err != nil {
+		return err
+	}
+
+	observeConversion(bannerID, slotID, value)
+	return nil
+}
+
+func observeConversion(bannerID, slotID *uuid.UUID, value float64) {
+	metrics.Conversions.WithLabelValues(slotID.String(), bannerID.String()).Inc()
+	metrics.Revenue.WithLabelValues(slotID.String(), bannerID.String()).Add(value)
 }
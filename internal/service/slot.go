@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+)
+
+func (s *Service) CreateSlot(ctx context.Context, slot *model.Slot) error {
+	return s.storage.CreateSlot(ctx, slot)
+}
+
+func (s *Service) GetSlot(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error) {
+	slot, err := s.storage.FindSlotByID(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if slot == nil {
+		return nil, errors.ErrSlotNotFound
+	}
+
+	return slot, nil
+}
+
+func (s *Service) ListSlots(ctx context.Context) ([]*model.Slot, error) {
+	return s.storage.FindSlots(ctx)
+}
+
+func (s *Service) UpdateSlot(ctx context.Context, slot *model.Slot) error {
+	_, err := s.GetSlot(ctx, &slot.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.flushAndInvalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.UpdateSlot(ctx, slot)
+}
+
+func (s *Service) DeleteSlot(ctx context.Context, slotID *uuid.UUID) error {
+	_, err := s.GetSlot(ctx, slotID)
+	if err != nil {
+		return err
+	}
+
+	err = s.flushAndInvalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.DeleteSlot(ctx, slotID)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+)
+
+func (s *Service) CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	return s.storage.CreateSocialGroup(ctx, socialGroup)
+}
+
+func (s *Service) GetSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error) {
+	socialGroup, err := s.storage.FindSocialGroupByID(ctx, socialGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if socialGroup == nil {
+		return nil, errors.ErrSocialGroupNotFound
+	}
+
+	return socialGroup, nil
+}
+
+func (s *Service) ListSocialGroups(ctx context.Context) ([]*model.Group, error) {
+	return s.storage.FindSocialGroups(ctx)
+}
+
+func (s *Service) UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	_, err := s.GetSocialGroup(ctx, &socialGroup.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.flushAndInvalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.UpdateSocialGroup(ctx, socialGroup)
+}
+
+func (s *Service) DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error {
+	_, err := s.GetSocialGroup(ctx, socialGroupID)
+	if err != nil {
+		return err
+	}
+
+	err = s.flushAndInvalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.DeleteSocialGroup(ctx, socialGroupID)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"math"
+)
+
+// wilsonZ is the standard normal quantile of the 95% confidence interval.
+const wilsonZ = 1.96
+
+func (s *Service) GetStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error) {
+	seen := make(map[string]bool, len(filter.GroupBy))
+	for _, dimension := range filter.GroupBy {
+		switch dimension {
+		case model.DimensionBanner, model.DimensionSlot, model.DimensionGroup:
+		default:
+			return nil, errors.ErrUnknownStatDimension
+		}
+
+		if seen[dimension] {
+			return nil, errors.ErrRepeatedStatDimension
+		}
+		seen[dimension] = true
+	}
+
+	reports, err := s.storage.FindStatReports(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, report := range reports {
+		report.CTR, report.CTRLow, report.CTRHigh = wilsonInterval(report.Clicks, report.Shows)
+	}
+
+	return reports, nil
+}
+
+// wilsonInterval returns the click-through rate and its Wilson score interval.
+func wilsonInterval(clicks, shows int64) (ctr, low, high float64) {
+	if shows <= 0 {
+		return 0, 0, 0
+	}
+
+	n := float64(shows)
+	ctr = math.Min(float64(clicks)/n, 1)
+
+	denominator := 1 + wilsonZ*wilsonZ/n
+	center := (ctr + wilsonZ*wilsonZ/(2*n)) / denominator
+	margin := wilsonZ * math.Sqrt(ctr*(1-ctr)/n+wilsonZ*wilsonZ/(4*n*n)) / denominator
+
+	return ctr, math.Max(center-margin, 0), math.Min(center+margin, 1)
+}
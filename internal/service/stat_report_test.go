@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	for _, tc := range []struct {
+		clicks, shows  int64
+		ctr, low, high float64
+	}{
+		{clicks: 0, shows: 0, ctr: 0, low: 0, high: 0},
+		{clicks: 10, shows: 100, ctr: 0.1, low: 0.055229, high: 0.174367},
+		{clicks: 0, shows: 10, ctr: 0, low: 0, high: 0.277540},
+		{clicks: 10, shows: 10, ctr: 1, low: 0.722460, high: 1},
+		{clicks: 1, shows: 1000, ctr: 0.001, low: 0.000177, high: 0.005643},
+		{clicks: 12, shows: 10, ctr: 1, low: 0.722460, high: 1},
+	} {
+		ctr, low, high := wilsonInterval(tc.clicks, tc.shows)
+
+		require.InDelta(t, tc.ctr, ctr, 1e-6, "%d/%d", tc.clicks, tc.shows)
+		require.InDelta(t, tc.low, low, 1e-6, "%d/%d", tc.clicks, tc.shows)
+		require.InDelta(t, tc.high, high, 1e-6, "%d/%d", tc.clicks, tc.shows)
+	}
+}
+
+func TestGetStatReportsDimensions(t *testing.T) {
+	s := &Service{}
+
+	_, err := s.GetStatReports(context.Background(), &model.StatFilter{GroupBy: []string{model.DimensionSlot, "country"}})
+	require.ErrorIs(t, err, errors.ErrUnknownStatDimension)
+
+	_, err = s.GetStatReports(context.Background(), &model.StatFilter{GroupBy: []string{model.DimensionSlot, model.DimensionSlot}})
+	require.ErrorIs(t, err, errors.ErrRepeatedStatDimension)
+}
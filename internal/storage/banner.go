@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+func (s *Storage) CreateBanner(ctx context.Context, banner *model.Banner) error {
+	ctx, done := s.observe(ctx, "CreateBanner")
+	defer done()
+
+	query := `
+		INSERT INTO banner(description)
+		VALUES ($1)
+		RETURNING id
+	`
+
+	err := s.client.QueryRow(ctx, query, banner.Description).Scan(&banner.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) FindBanners(ctx context.Context) ([]*model.Banner, error) {
+	ctx, done := s.observe(ctx, "FindBanners")
+	defer done()
+
+	query := `
+		SELECT id, description
+		FROM banner
+		ORDER BY id
+	`
+
+	var banners []*model.Banner
+
+	err := pgxscan.Select(ctx, s.client, &banners, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return banners, nil
+}
+
+func (s *Storage) UpdateBanner(ctx context.Context, banner *model.Banner) error {
+	ctx, done := s.observe(ctx, "UpdateBanner")
+	defer done()
+
+	query := `
+		UPDATE banner
+		SET description = $2
+		WHERE id = $1
+	`
+
+	_, err := s.client.Exec(ctx, query, banner.ID, banner.Description)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBanner deletes the banner together with its statistics. It fails if the banner is linked to a slot.
+func (s *Storage) DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error {
+	ctx, done := s.observe(ctx, "DeleteBanner")
+	defer done()
+
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		// FOR UPDATE waits for the transactions that link the banner, they hold it FOR SHARE
+		_, err := tx.Exec(ctx, `SELECT id FROM banner WHERE id = $1 FOR UPDATE`, bannerID)
+		if err != nil {
+			return err
+		}
+
+		var linked bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM banner_slot WHERE banner_id = $1)`, bannerID).Scan(&linked)
+		if err != nil {
+			return err
+		}
+		if linked {
+			return errors.ErrBannerLinkedToSlot
+		}
+
+		queries := []string{
+			`DELETE FROM stat_bucket WHERE banner_id = $1`,
+			`DELETE FROM stat WHERE banner_id = $1`,
+			`DELETE FROM banner WHERE id = $1`,
+		}
+
+		for _, query := range queries {
+			_, err = tx.Exec(ctx, query, bannerID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// bannerSlotState is what is known about the banners and slots of a bulk request inside its transaction.
+type bannerSlotState struct {
+	banners map[uuid.UUID]bool
+	slots   map[uuid.UUID]bool
+	links   map[model.BannerSlot]bool
+}
+
+func findBannerSlotState(ctx context.Context, tx pgx.Tx, bannerSlots []*model.BannerSlot) (*bannerSlotState, error) {
+	bannerIDs := make([]uuid.UUID, len(bannerSlots))
+	slotIDs := make([]uuid.UUID, len(bannerSlots))
+	for i, bannerSlot := range bannerSlots {
+		bannerIDs[i] = bannerSlot.BannerID
+		slotIDs[i] = bannerSlot.SlotID
+	}
+
+	state := &bannerSlotState{
+		banners: make(map[uuid.UUID]bool),
+		slots:   make(map[uuid.UUID]bool),
+		links:   make(map[model.BannerSlot]bool),
+	}
+
+	// FOR SHARE keeps the banners and slots from being deleted until the transaction ends
+	var ids []uuid.UUID
+	err := pgxscan.Select(ctx, tx, &ids, `SELECT id FROM banner WHERE id = ANY($1) FOR SHARE`, bannerIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		state.banners[id] = true
+	}
+
+	ids = nil
+	err = pgxscan.Select(ctx, tx, &ids, `SELECT id FROM slot WHERE id = ANY($1) FOR SHARE`, slotIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		state.slots[id] = true
+	}
+
+	query := `
+		SELECT banner_id, slot_id
+		FROM banner_slot
+		WHERE (banner_id, slot_id) IN (SELECT * FROM unnest($1::uuid[], $2::uuid[]))
+		FOR UPDATE;
+	`
+
+	var links []*model.BannerSlot
+	err = pgxscan.Select(ctx, tx, &links, query, bannerIDs, slotIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		state.links[*link] = true
+	}
+
+	return state, nil
+}
+
+// AddBannersToSlots links the pairs in one transaction. It returns why every pair that was not linked
+// failed, at the index of the pair, and nil for the pairs that were linked.
+func (s *Storage) AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error) {
+	ctx, done := s.observe(ctx, "AddBannersToSlots")
+	defer done()
+
+	results := make([]error, len(bannerSlots))
+
+	err := s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		state, err := findBannerSlotState(ctx, tx, bannerSlots)
+		if err != nil {
+			return err
+		}
+
+		var (
+			bannerIDs, slotIDs []uuid.UUID
+			inserted           = make(map[model.BannerSlot]int)
+		)
+		for i, bannerSlot := range bannerSlots {
+			switch {
+			case !state.banners[bannerSlot.BannerID]:
+				results[i] = errors.ErrBannerNotFound
+			case !state.slots[bannerSlot.SlotID]:
+				results[i] = errors.ErrSlotNotFound
+			case state.links[*bannerSlot]:
+				results[i] = errors.ErrBannerAlreadyLinkedToSlot
+			default:
+				state.links[*bannerSlot] = true
+				inserted[*bannerSlot] = i
+				bannerIDs = append(bannerIDs, bannerSlot.BannerID)
+				slotIDs = append(slotIDs, bannerSlot.SlotID)
+			}
+		}
+
+		if len(bannerIDs) == 0 {
+			return nil
+		}
+
+		// a concurrent request may insert the same pairs after they were checked
+		query := `
+			INSERT INTO banner_slot(banner_id, slot_id)
+			SELECT * FROM unnest($1::uuid[], $2::uuid[])
+			ON CONFLICT DO NOTHING
+			RETURNING banner_id, slot_id;
+		`
+
+		var links []*model.BannerSlot
+		err = pgxscan.Select(ctx, tx, &links, query, bannerIDs, slotIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			delete(inserted, *link)
+		}
+		for _, i := range inserted {
+			results[i] = errors.ErrBannerAlreadyLinkedToSlot
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// RemoveBannersFromSlots unlinks the pairs in one transaction. It returns why every pair that was not
+// unlinked failed, at the index of the pair, and nil for the pairs that were unlinked.
+func (s *Storage) RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error) {
+	ctx, done := s.observe(ctx, "RemoveBannersFromSlots")
+	defer done()
+
+	results := make([]error, len(bannerSlots))
+
+	err := s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		state, err := findBannerSlotState(ctx, tx, bannerSlots)
+		if err != nil {
+			return err
+		}
+
+		var bannerIDs, slotIDs []uuid.UUID
+		for i, bannerSlot := range bannerSlots {
+			switch {
+			case !state.banners[bannerSlot.BannerID]:
+				results[i] = errors.ErrBannerNotFound
+			case !state.slots[bannerSlot.SlotID]:
+				results[i] = errors.ErrSlotNotFound
+			case !state.links[*bannerSlot]:
+				results[i] = errors.ErrBannerNotLinkedToSlot
+			default:
+				state.links[*bannerSlot] = false
+				bannerIDs = append(bannerIDs, bannerSlot.BannerID)
+				slotIDs = append(slotIDs, bannerSlot.SlotID)
+			}
+		}
+
+		if len(bannerIDs) == 0 {
+			return nil
+		}
+
+		query := `
+			DELETE FROM banner_slot
+			WHERE (banner_id, slot_id) IN (SELECT * FROM unnest($1::uuid[], $2::uuid[]));
+		`
+		_, err = tx.Exec(ctx, query, bannerIDs, slotIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
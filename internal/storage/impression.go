@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	stdErrors "errors"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+const addImpressionQuery = `
+	INSERT INTO impression(id, banner_id, slot_id, social_group_id, shown_at, features)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (id) DO NOTHING
+`
+
+func addImpression(ctx context.Context, tx pgx.Tx, event *model.Event) error {
+	_, err := tx.Exec(ctx, addImpressionQuery, event.ID, event.BannerID, event.SlotID, event.GroupID, event.Timestamp,
+		event.Features)
+	return err
+}
+
+// rewardImpressionQueries mark the impression of a click or conversion, unless it already has one.
+var rewardImpressionQueries = map[string]string{
+	model.EventClick: `
+		UPDATE impression SET clicked_at = $2
+		WHERE id = $1 AND clicked_at IS NULL
+	`,
+	model.EventConversion: `
+		UPDATE impression SET converted_at = $2
+		WHERE id = $1 AND converted_at IS NULL
+	`,
+}
+
+// rewardImpression marks the impression the click or conversion names, if it names one. It fails with
+// ErrImpressionAlreadyRewarded if the impression already has one, so the transaction does not count it again.
+func rewardImpression(ctx context.Context, tx pgx.Tx, event *model.Event) error {
+	if event.ImpressionID == nil {
+		return nil
+	}
+
+	tag, err := tx.Exec(ctx, rewardImpressionQueries[event.Type], event.ImpressionID, event.Timestamp)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != 0 {
+		return nil
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM impression WHERE id = $1)`, event.ImpressionID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.ErrImpressionNotFound
+	}
+
+	return errors.ErrImpressionAlreadyRewarded
+}
+
+func (s *Storage) FindImpressionByID(ctx context.Context, impressionID *uuid.UUID) (*model.Impression, error) {
+	ctx, done := s.observe(ctx, "FindImpressionByID")
+	defer done()
+
+	query := `
+		SELECT id, banner_id, slot_id, social_group_id, shown_at, features, clicked_at, converted_at
+		FROM impression
+		WHERE id = $1
+	`
+
+	var impression model.Impression
+	err := pgxscan.Get(ctx, s.client, &impression, query, impressionID)
+	if err != nil {
+		if stdErrors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &impression, nil
+}
+
+// DeleteImpressionsBefore deletes the impressions shown before the time and returns how many there were.
+func (s *Storage) DeleteImpressionsBefore(ctx context.Context, before time.Time) (int, error) {
+	ctx, done := s.observe(ctx, "DeleteImpressionsBefore")
+	defer done()
+
+	query := `
+		DELETE FROM impression
+		WHERE shown_at < $1
+	`
+
+	tag, err := s.client.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"sort"
+)
+
+func (s *Storage) CreateBanner(ctx context.Context, banner *model.Banner) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	banner.ID = uuid.New()
+	s.banners[banner.ID] = *banner
+	return nil
+}
+
+func (s *Storage) FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	banner, ok := s.banners[*bannerID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &banner, nil
+}
+
+func (s *Storage) FindBanners(ctx context.Context) ([]*model.Banner, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	banners := make([]*model.Banner, 0, len(s.banners))
+	for _, banner := range s.banners {
+		banner := banner
+		banners = append(banners, &banner)
+	}
+	sort.Slice(banners, func(i, j int) bool {
+		return banners[i].ID.String() < banners[j].ID.String()
+	})
+
+	return banners, nil
+}
+
+func (s *Storage) UpdateBanner(ctx context.Context, banner *model.Banner) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.banners[banner.ID]; ok {
+		s.banners[banner.ID] = *banner
+	}
+	return nil
+}
+
+// DeleteBanner deletes the banner together with its statistics. It fails if the banner is linked to a slot.
+func (s *Storage) DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.bannerSlots {
+		if key.bannerID == *bannerID {
+			return errors.ErrBannerLinkedToSlot
+		}
+	}
+
+	s.deleteStats(func(key statKey) bool {
+		return key.bannerID == *bannerID
+	})
+	delete(s.banners, *bannerID)
+	return nil
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (s *Storage) FindImpressionByID(ctx context.Context, impressionID *uuid.UUID) (*model.Impression, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	impression, ok := s.impressions[*impressionID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &impression, nil
+}
+
+// DeleteImpressionsBefore deletes the impressions shown before the time and returns how many there were.
+func (s *Storage) DeleteImpressionsBefore(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int
+	for id, impression := range s.impressions {
+		if impression.ShownAt.Before(before) {
+			delete(s.impressions, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
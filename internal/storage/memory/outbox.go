@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"time"
+)
+
+// RelayEvents passes up to limit of the oldest unsent events to publish and removes them
+// from the outbox if publish succeeds.
+func (s *Storage) RelayEvents(ctx context.Context, limit int, publish func(events []*model.Event) error) (int, error) {
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+
+	s.mu.RLock()
+	events := make([]*model.Event, 0, limit)
+	for i := 0; i < len(s.outbox) && i < limit; i++ {
+		eventCopy := *s.outbox[i]
+		events = append(events, &eventCopy)
+	}
+	s.mu.RUnlock()
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	err := publish(events)
+	if err != nil {
+		return 0, err
+	}
+
+	// events are only appended while the relay publishes, so the relayed ones are still first
+	s.mu.Lock()
+	s.outbox = s.outbox[len(events):]
+	s.mu.Unlock()
+
+	return len(events), nil
+}
+
+// DeleteEventsSentBefore deletes nothing, relayed events are removed from the outbox right away.
+func (s *Storage) DeleteEventsSentBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,350 @@
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"sort"
+	"time"
+)
+
+func (s *Storage) AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := bannerSlotKey{bannerID: *bannerID, slotID: *slotID}
+	if err := s.checkBannerSlot(key); err != nil {
+		return err
+	}
+
+	if _, ok := s.bannerSlots[key]; ok {
+		return errors.ErrBannerAlreadyLinkedToSlot
+	}
+
+	s.bannerSlots[key] = struct{}{}
+	return nil
+}
+
+func (s *Storage) FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUID) (*model.BannerSlot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.bannerSlots[bannerSlotKey{bannerID: *bannerID, slotID: *slotID}]; !ok {
+		return nil, nil
+	}
+
+	return &model.BannerSlot{
+		BannerID: *bannerID,
+		SlotID:   *slotID,
+	}, nil
+}
+
+func (s *Storage) RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.bannerSlots, bannerSlotKey{bannerID: *bannerID, slotID: *slotID})
+	return nil
+}
+
+func (s *Storage) FindBannersInSlot(ctx context.Context, slotID *uuid.UUID) ([]*uuid.UUID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var bannerIDs []*uuid.UUID
+	for key := range s.bannerSlots {
+		if key.slotID == *slotID {
+			bannerID := key.bannerID
+			bannerIDs = append(bannerIDs, &bannerID)
+		}
+	}
+
+	return sortIDs(bannerIDs), nil
+}
+
+func (s *Storage) FindSlotsOfBanner(ctx context.Context, bannerID *uuid.UUID) ([]*uuid.UUID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var slotIDs []*uuid.UUID
+	for key := range s.bannerSlots {
+		if key.bannerID == *bannerID {
+			slotID := key.slotID
+			slotIDs = append(slotIDs, &slotID)
+		}
+	}
+
+	return sortIDs(slotIDs), nil
+}
+
+func sortIDs(ids []*uuid.UUID) []*uuid.UUID {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+func (s *Storage) FindStatByParams(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) (*model.Stat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stat, ok := s.stats[statKey{bannerID: *bannerID, slotID: *slotID, socialGroupID: *socialGroupID}]
+	if !ok {
+		return nil, nil
+	}
+
+	statCopy := *stat
+	return &statCopy, nil
+}
+
+func (s *Storage) FindStatsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID) ([]*model.Stat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stats []*model.Stat
+	for key, stat := range s.stats {
+		if key.slotID == *slotID && key.socialGroupID == *socialGroupID {
+			statCopy := *stat
+			stats = append(stats, &statCopy)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].BannerID.String() < stats[j].BannerID.String()
+	})
+
+	return stats, nil
+}
+
+func (s *Storage) FindStatBucketsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID, since time.Time) ([]*model.StatBucket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var buckets []*model.StatBucket
+	for key, bucket := range s.statBuckets {
+		if key.slotID == *slotID && key.socialGroupID == *socialGroupID && !key.bucket.Before(since) {
+			bucketCopy := *bucket
+			buckets = append(buckets, &bucketCopy)
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if !buckets[i].Bucket.Equal(buckets[j].Bucket) {
+			return buckets[i].Bucket.Before(buckets[j].Bucket)
+		}
+		return buckets[i].BannerID.String() < buckets[j].BannerID.String()
+	})
+
+	return buckets, nil
+}
+
+// AddClickToStat counts the click, marks its impression and records the event in the outbox. It fails with
+// ErrImpressionAlreadyRewarded if the impression was clicked.
+func (s *Storage) AddClickToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	return s.addRewardToStat(&model.StatDelta{
+		BannerID: stat.BannerID,
+		SlotID:   stat.SlotID,
+		GroupID:  stat.GroupID,
+		Bucket:   event.Timestamp,
+		Clicks:   1,
+	}, event)
+}
+
+// AddConversionToStat counts the conversion with the value of the event, marks its impression and records
+// the event in the outbox. It fails with ErrImpressionAlreadyRewarded if the impression converted.
+func (s *Storage) AddConversionToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	return s.addRewardToStat(&model.StatDelta{
+		BannerID:    stat.BannerID,
+		SlotID:      stat.SlotID,
+		GroupID:     stat.GroupID,
+		Bucket:      event.Timestamp,
+		Conversions: 1,
+		Revenue:     event.Value,
+	}, event)
+}
+
+// addRewardToStat adds the delta of the click or conversion unless its impression already has one.
+func (s *Storage) addRewardToStat(delta *model.StatDelta, event *model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.ImpressionID != nil {
+		impression, ok := s.impressions[*event.ImpressionID]
+		if !ok {
+			return errors.ErrImpressionNotFound
+		}
+		if impression.Rewarded(event.Type) {
+			return errors.ErrImpressionAlreadyRewarded
+		}
+	}
+
+	return s.addStatDeltas([]*model.StatDelta{delta}, []*model.Event{event})
+}
+
+// AddShowToStat counts the show and records the event in the outbox and its impression.
+func (s *Storage) AddShowToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	return s.AddStatDeltas(ctx, []*model.StatDelta{{
+		BannerID: stat.BannerID,
+		SlotID:   stat.SlotID,
+		GroupID:  stat.GroupID,
+		Bucket:   event.Timestamp,
+		Shows:    1,
+	}}, []*model.Event{event})
+}
+
+// AddStatDeltas adds the deltas to the statistics, records the events in the outbox, the impressions
+// of the show events and the clicks and conversions of the impressions. Nothing is changed if a banner,
+// slot or social group of a delta does not exist.
+func (s *Storage) AddStatDeltas(ctx context.Context, deltas []*model.StatDelta, events []*model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addStatDeltas(deltas, events)
+}
+
+// addStatDeltas must be called with s.mu held.
+func (s *Storage) addStatDeltas(deltas []*model.StatDelta, events []*model.Event) error {
+	for _, delta := range deltas {
+		if _, ok := s.banners[delta.BannerID]; !ok {
+			return errors.ErrBannerNotFound
+		}
+		if _, ok := s.slots[delta.SlotID]; !ok {
+			return errors.ErrSlotNotFound
+		}
+		if _, ok := s.socialGroups[delta.GroupID]; !ok {
+			return errors.ErrSocialGroupNotFound
+		}
+	}
+
+	for _, delta := range deltas {
+		key := statKey{bannerID: delta.BannerID, slotID: delta.SlotID, socialGroupID: delta.GroupID}
+
+		stat, ok := s.stats[key]
+		if !ok {
+			stat = &model.Stat{
+				BannerID: delta.BannerID,
+				SlotID:   delta.SlotID,
+				GroupID:  delta.GroupID,
+			}
+			s.stats[key] = stat
+		}
+		stat.Shows += delta.Shows
+		stat.Clicks += delta.Clicks
+		stat.Conversions += delta.Conversions
+		stat.Revenue += delta.Revenue
+
+		bucketKey := statBucketKey{statKey: key, bucket: delta.Bucket.Truncate(time.Hour).UTC()}
+
+		bucket, ok := s.statBuckets[bucketKey]
+		if !ok {
+			bucket = &model.StatBucket{
+				BannerID: delta.BannerID,
+				SlotID:   delta.SlotID,
+				GroupID:  delta.GroupID,
+				Bucket:   bucketKey.bucket,
+			}
+			s.statBuckets[bucketKey] = bucket
+		}
+		bucket.Shows += delta.Shows
+		bucket.Clicks += delta.Clicks
+		bucket.Conversions += delta.Conversions
+		bucket.Revenue += delta.Revenue
+	}
+
+	for _, event := range events {
+		eventCopy := *event
+		s.outbox = append(s.outbox, &eventCopy)
+
+		if event.Type == model.EventShow {
+			s.impressions[event.ID] = *model.ImpressionOf(event)
+			continue
+		}
+
+		if event.ImpressionID == nil {
+			continue
+		}
+		if impression, ok := s.impressions[*event.ImpressionID]; ok && !impression.Rewarded(event.Type) {
+			impression.Reward(event)
+			s.impressions[*event.ImpressionID] = impression
+		}
+	}
+
+	return nil
+}
+
+// deleteStats must be called with s.mu held.
+func (s *Storage) deleteStats(match func(key statKey) bool) {
+	for key := range s.stats {
+		if match(key) {
+			delete(s.stats, key)
+		}
+	}
+
+	for key := range s.statBuckets {
+		if match(key.statKey) {
+			delete(s.statBuckets, key)
+		}
+	}
+}
+
+// AddBannersToSlots links the pairs at once. It returns why every pair that was not linked
+// failed, at the index of the pair, and nil for the pairs that were linked.
+func (s *Storage) AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]error, len(bannerSlots))
+	for i, bannerSlot := range bannerSlots {
+		key := bannerSlotKey{bannerID: bannerSlot.BannerID, slotID: bannerSlot.SlotID}
+
+		if results[i] = s.checkBannerSlot(key); results[i] != nil {
+			continue
+		}
+
+		if _, ok := s.bannerSlots[key]; ok {
+			results[i] = errors.ErrBannerAlreadyLinkedToSlot
+			continue
+		}
+
+		s.bannerSlots[key] = struct{}{}
+	}
+
+	return results, nil
+}
+
+// RemoveBannersFromSlots unlinks the pairs at once. It returns why every pair that was not
+// unlinked failed, at the index of the pair, and nil for the pairs that were unlinked.
+func (s *Storage) RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]error, len(bannerSlots))
+	for i, bannerSlot := range bannerSlots {
+		key := bannerSlotKey{bannerID: bannerSlot.BannerID, slotID: bannerSlot.SlotID}
+
+		if results[i] = s.checkBannerSlot(key); results[i] != nil {
+			continue
+		}
+
+		if _, ok := s.bannerSlots[key]; !ok {
+			results[i] = errors.ErrBannerNotLinkedToSlot
+			continue
+		}
+
+		delete(s.bannerSlots, key)
+	}
+
+	return results, nil
+}
+
+// checkBannerSlot must be called with s.mu held.
+func (s *Storage) checkBannerSlot(key bannerSlotKey) error {
+	if _, ok := s.banners[key.bannerID]; !ok {
+		return errors.ErrBannerNotFound
+	}
+
+	if _, ok := s.slots[key.slotID]; !ok {
+		return errors.ErrSlotNotFound
+	}
+
+	return nil
+}
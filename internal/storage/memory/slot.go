@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"sort"
+)
+
+func (s *Storage) CreateSlot(ctx context.Context, slot *model.Slot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot.ID = uuid.New()
+	s.slots[slot.ID] = *slot
+	return nil
+}
+
+func (s *Storage) FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	slot, ok := s.slots[*slotID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &slot, nil
+}
+
+func (s *Storage) FindSlots(ctx context.Context) ([]*model.Slot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	slots := make([]*model.Slot, 0, len(s.slots))
+	for _, slot := range s.slots {
+		slot := slot
+		slots = append(slots, &slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].ID.String() < slots[j].ID.String()
+	})
+
+	return slots, nil
+}
+
+func (s *Storage) UpdateSlot(ctx context.Context, slot *model.Slot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.slots[slot.ID]; ok {
+		s.slots[slot.ID] = *slot
+	}
+	return nil
+}
+
+// DeleteSlot deletes the slot together with its statistics. It fails if the slot has linked banners.
+func (s *Storage) DeleteSlot(ctx context.Context, slotID *uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.bannerSlots {
+		if key.slotID == *slotID {
+			return errors.ErrSlotHasBanners
+		}
+	}
+
+	s.deleteStats(func(key statKey) bool {
+		return key.slotID == *slotID
+	})
+	delete(s.slots, *slotID)
+	return nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"sort"
+)
+
+func (s *Storage) CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	socialGroup.ID = uuid.New()
+	s.socialGroups[socialGroup.ID] = *socialGroup
+	return nil
+}
+
+func (s *Storage) FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	socialGroup, ok := s.socialGroups[*socialGroupID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &socialGroup, nil
+}
+
+func (s *Storage) FindSocialGroups(ctx context.Context) ([]*model.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	socialGroups := make([]*model.Group, 0, len(s.socialGroups))
+	for _, socialGroup := range s.socialGroups {
+		socialGroup := socialGroup
+		socialGroups = append(socialGroups, &socialGroup)
+	}
+	sort.Slice(socialGroups, func(i, j int) bool {
+		return socialGroups[i].ID.String() < socialGroups[j].ID.String()
+	})
+
+	return socialGroups, nil
+}
+
+func (s *Storage) UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.socialGroups[socialGroup.ID]; ok {
+		s.socialGroups[socialGroup.ID] = *socialGroup
+	}
+	return nil
+}
+
+// DeleteSocialGroup deletes the social group together with its statistics.
+func (s *Storage) DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteStats(func(key statKey) bool {
+		return key.socialGroupID == *socialGroupID
+	})
+	delete(s.socialGroups, *socialGroupID)
+	return nil
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"sort"
+)
+
+func (s *Storage) FindStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error) {
+	for _, dimension := range filter.GroupBy {
+		switch dimension {
+		case model.DimensionBanner, model.DimensionSlot, model.DimensionGroup:
+		default:
+			return nil, fmt.Errorf("unknown dimension %q", dimension)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reports := make(map[statKey]*model.StatReport)
+	// without dimensions everything is summed up into one row, even if there are no statistics
+	if len(filter.GroupBy) == 0 {
+		reports[statKey{}] = &model.StatReport{}
+	}
+
+	for _, stat := range s.stats {
+		if filter.BannerID != nil && *filter.BannerID != stat.BannerID ||
+			filter.SlotID != nil && *filter.SlotID != stat.SlotID ||
+			filter.GroupID != nil && *filter.GroupID != stat.GroupID {
+			continue
+		}
+
+		var key statKey
+		for _, dimension := range filter.GroupBy {
+			switch dimension {
+			case model.DimensionBanner:
+				key.bannerID = stat.BannerID
+			case model.DimensionSlot:
+				key.slotID = stat.SlotID
+			case model.DimensionGroup:
+				key.socialGroupID = stat.GroupID
+			}
+		}
+
+		report, ok := reports[key]
+		if !ok {
+			report = &model.StatReport{}
+			for _, dimension := range filter.GroupBy {
+				switch dimension {
+				case model.DimensionBanner:
+					report.BannerID = &key.bannerID
+				case model.DimensionSlot:
+					report.SlotID = &key.slotID
+				case model.DimensionGroup:
+					report.GroupID = &key.socialGroupID
+				}
+			}
+			reports[key] = report
+		}
+
+		report.Shows += int64(stat.Shows)
+		report.Clicks += int64(stat.Clicks)
+		report.Conversions += int64(stat.Conversions)
+		report.Revenue += stat.Revenue
+	}
+
+	result := make([]*model.StatReport, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, report)
+	}
+
+	// ordered by the dimensions in the order they were requested, like ORDER BY in the PostgreSQL storage
+	sort.Slice(result, func(i, j int) bool {
+		for _, dimension := range filter.GroupBy {
+			var a, b string
+			switch dimension {
+			case model.DimensionBanner:
+				a, b = result[i].BannerID.String(), result[j].BannerID.String()
+			case model.DimensionSlot:
+				a, b = result[i].SlotID.String(), result[j].SlotID.String()
+			case model.DimensionGroup:
+				a, b = result[i].GroupID.String(), result[j].GroupID.String()
+			}
+			if a != b {
+				return a < b
+			}
+		}
+		return false
+	})
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+// Package memory keeps banners, slots, social groups and their statistics in memory.
+// It behaves like the PostgreSQL storage and is meant for tests and local development.
+package memory
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+type bannerSlotKey struct {
+	bannerID uuid.UUID
+	slotID   uuid.UUID
+}
+
+type statKey struct {
+	bannerID      uuid.UUID
+	slotID        uuid.UUID
+	socialGroupID uuid.UUID
+}
+
+type statBucketKey struct {
+	statKey
+	bucket time.Time
+}
+
+type Storage struct {
+	mu           sync.RWMutex
+	banners      map[uuid.UUID]model.Banner
+	slots        map[uuid.UUID]model.Slot
+	socialGroups map[uuid.UUID]model.Group
+	bannerSlots  map[bannerSlotKey]struct{}
+	stats        map[statKey]*model.Stat
+	statBuckets  map[statBucketKey]*model.StatBucket
+	outbox       []*model.Event
+	impressions  map[uuid.UUID]model.Impression
+
+	// relayMu lets only one relay publish the outbox at a time.
+	relayMu sync.Mutex
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		banners:      make(map[uuid.UUID]model.Banner),
+		slots:        make(map[uuid.UUID]model.Slot),
+		socialGroups: make(map[uuid.UUID]model.Group),
+		bannerSlots:  make(map[bannerSlotKey]struct{}),
+		stats:        make(map[statKey]*model.Stat),
+		statBuckets:  make(map[statBucketKey]*model.StatBucket),
+		impressions:  make(map[uuid.UUID]model.Impression),
+	}
+}
+
+// Ping never fails, the storage is always reachable.
+func (s *Storage) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,12 @@
+package memory
+
+import (
+	"github.com/aakosarev/banner-rotation/internal/storage/storagetest"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return NewStorage()
+	})
+}
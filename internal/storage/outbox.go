@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+const addEventToOutboxQuery = `
+	INSERT INTO outbox(id, payload)
+	VALUES ($1, $2)
+	ON CONFLICT (id) DO NOTHING
+`
+
+func addEventToOutbox(ctx context.Context, tx pgx.Tx, event *model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, addEventToOutboxQuery, event.ID, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type outboxRow struct {
+	ID        uuid.UUID `db:"id"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// outboxClaim is how long a relay may publish the events it claimed before other relays take them over.
+const outboxClaim = time.Minute
+
+// RelayEvents claims up to limit unsent events, passes them to publish and marks them as sent
+// if publish succeeds. Nothing is locked while publish runs, concurrent relays skip the claimed events
+// until the claim expires.
+func (s *Storage) RelayEvents(ctx context.Context, limit int, publish func(events []*model.Event) error) (int, error) {
+	ctx, done := s.observe(ctx, "RelayEvents")
+	defer done()
+
+	query := `
+		UPDATE outbox
+		SET claimed_until = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE sent_at IS NULL AND (claimed_until IS NULL OR claimed_until < now())
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, payload, created_at
+	`
+
+	var claimed []*outboxRow
+
+	err := pgxscan.Select(ctx, s.client, &claimed, query, limit, outboxClaim.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	if len(claimed) == 0 {
+		return 0, nil
+	}
+
+	sort.Slice(claimed, func(i, j int) bool {
+		return claimed[i].CreatedAt.Before(claimed[j].CreatedAt)
+	})
+
+	ids := make([]uuid.UUID, len(claimed))
+	events := make([]*model.Event, len(claimed))
+	for i, row := range claimed {
+		event := &model.Event{}
+		err = json.Unmarshal(row.Payload, event)
+		if err != nil {
+			return 0, err
+		}
+
+		ids[i] = row.ID
+		events[i] = event
+	}
+
+	err = publish(events)
+	if err != nil {
+		// other relays may take the events over right away
+		_, releaseErr := s.client.Exec(ctx, `UPDATE outbox SET claimed_until = NULL WHERE id = ANY($1)`, ids)
+		if releaseErr != nil {
+			s.logger.WarnContext(ctx, "failed to release outbox events", slog.Any("error", releaseErr))
+		}
+		return 0, err
+	}
+
+	query = `
+		UPDATE outbox
+		SET sent_at = now(), claimed_until = NULL
+		WHERE id = ANY($1)
+	`
+
+	_, err = s.client.Exec(ctx, query, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// DeleteEventsSentBefore deletes the events sent before the time and returns how many there were.
+func (s *Storage) DeleteEventsSentBefore(ctx context.Context, before time.Time) (int, error) {
+	ctx, done := s.observe(ctx, "DeleteEventsSentBefore")
+	defer done()
+
+	query := `
+		DELETE FROM outbox
+		WHERE sent_at < $1
+	`
+
+	tag, err := s.client.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
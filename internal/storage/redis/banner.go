@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateBanner(ctx context.Context, banner *model.Banner) error {
+	id, err := s.createEntity(ctx, bannerKind, banner.Description)
+	if err != nil {
+		return err
+	}
+
+	banner.ID = id
+	return nil
+}
+
+func (s *Storage) FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error) {
+	description, ok, err := s.findEntity(ctx, bannerKind, *bannerID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &model.Banner{
+		ID:          *bannerID,
+		Description: description,
+	}, nil
+}
+
+func (s *Storage) FindBanners(ctx context.Context) ([]*model.Banner, error) {
+	ids, descriptions, err := s.findEntities(ctx, bannerKind)
+	if err != nil {
+		return nil, err
+	}
+
+	banners := make([]*model.Banner, len(ids))
+	for i := range ids {
+		banners[i] = &model.Banner{
+			ID:          ids[i],
+			Description: descriptions[i],
+		}
+	}
+
+	return banners, nil
+}
+
+func (s *Storage) UpdateBanner(ctx context.Context, banner *model.Banner) error {
+	return s.updateEntity(ctx, bannerKind, banner.ID, banner.Description)
+}
+
+// DeleteBanner deletes the banner together with its statistics. It fails if the banner is linked to a slot.
+func (s *Storage) DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error {
+	err := s.deleteUnlinkedEntity(ctx, bannerKind, *bannerID, bannerSlotsKey(*bannerID), errors.ErrBannerLinkedToSlot)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteStats(ctx, bannerKind, *bannerID)
+}
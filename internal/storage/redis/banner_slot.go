@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// AddBannersToSlots links the pairs in one transaction. It returns why every pair that was not linked
+// failed, at the index of the pair, and nil for the pairs that were linked.
+func (s *Storage) AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error) {
+	return s.changeBannerSlots(ctx, bannerSlots, true)
+}
+
+// RemoveBannersFromSlots unlinks the pairs in one transaction. It returns why every pair that was not
+// unlinked failed, at the index of the pair, and nil for the pairs that were unlinked.
+func (s *Storage) RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error) {
+	return s.changeBannerSlots(ctx, bannerSlots, false)
+}
+
+// changeBannerSlots watches the banners, slots and links of the pairs, so the links are only changed
+// if nobody touched them since they were checked.
+func (s *Storage) changeBannerSlots(ctx context.Context, bannerSlots []*model.BannerSlot, link bool) ([]error, error) {
+	var keys []string
+	for _, bannerSlot := range bannerSlots {
+		keys = append(keys,
+			entityKey(bannerKind, bannerSlot.BannerID),
+			entityKey(slotKind, bannerSlot.SlotID),
+			slotBannersKey(bannerSlot.SlotID),
+		)
+	}
+
+	results := make([]error, len(bannerSlots))
+
+	err := s.client.Watch(ctx, func(tx *goredis.Tx) error {
+		var (
+			bannerExists = make([]*goredis.IntCmd, len(bannerSlots))
+			slotExists   = make([]*goredis.IntCmd, len(bannerSlots))
+			linked       = make([]*goredis.BoolCmd, len(bannerSlots))
+		)
+
+		_, err := tx.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for i, bannerSlot := range bannerSlots {
+				bannerExists[i] = pipe.Exists(ctx, entityKey(bannerKind, bannerSlot.BannerID))
+				slotExists[i] = pipe.Exists(ctx, entityKey(slotKind, bannerSlot.SlotID))
+				linked[i] = pipe.SIsMember(ctx, slotBannersKey(bannerSlot.SlotID), bannerSlot.BannerID.String())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		links := make(map[model.BannerSlot]bool)
+		for i, bannerSlot := range bannerSlots {
+			if _, ok := links[*bannerSlot]; !ok {
+				links[*bannerSlot] = linked[i].Val()
+			}
+
+			switch {
+			case bannerExists[i].Val() == 0:
+				results[i] = errors.ErrBannerNotFound
+			case slotExists[i].Val() == 0:
+				results[i] = errors.ErrSlotNotFound
+			case link && links[*bannerSlot]:
+				results[i] = errors.ErrBannerAlreadyLinkedToSlot
+			case !link && !links[*bannerSlot]:
+				results[i] = errors.ErrBannerNotLinkedToSlot
+			default:
+				results[i] = nil
+				links[*bannerSlot] = link
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for i, bannerSlot := range bannerSlots {
+				if results[i] != nil {
+					continue
+				}
+
+				if link {
+					pipe.SAdd(ctx, slotBannersKey(bannerSlot.SlotID), bannerSlot.BannerID.String())
+					pipe.SAdd(ctx, bannerSlotsKey(bannerSlot.BannerID), bannerSlot.SlotID.String())
+				} else {
+					pipe.SRem(ctx, slotBannersKey(bannerSlot.SlotID), bannerSlot.BannerID.String())
+					pipe.SRem(ctx, bannerSlotsKey(bannerSlot.BannerID), bannerSlot.SlotID.String())
+				}
+			}
+			return nil
+		})
+		return err
+	}, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"sort"
+)
+
+func (s *Storage) createEntity(ctx context.Context, kind, description string) (uuid.UUID, error) {
+	id := uuid.New()
+
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, entityKey(kind, id), descriptionField, description)
+		pipe.SAdd(ctx, entitiesKey(kind), id.String())
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// findEntity returns the description of the entity and false if there is no such entity.
+func (s *Storage) findEntity(ctx context.Context, kind string, id uuid.UUID) (string, bool, error) {
+	description, err := s.client.HGet(ctx, entityKey(kind, id), descriptionField).Result()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return description, true, nil
+}
+
+// findEntities returns the descriptions of all entities of the kind, ordered by ID.
+func (s *Storage) findEntities(ctx context.Context, kind string) ([]uuid.UUID, []string, error) {
+	members, err := s.client.SMembers(ctx, entitiesKey(kind)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(members)
+
+	ids := make([]uuid.UUID, 0, len(members))
+	commands := make([]*goredis.StringCmd, 0, len(members))
+
+	_, err = s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, member := range members {
+			id, err := uuid.Parse(member)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			commands = append(commands, pipe.HGet(ctx, entityKey(kind, id), descriptionField))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, nil, err
+	}
+
+	descriptions := make([]string, len(commands))
+	for i, command := range commands {
+		descriptions[i] = command.Val()
+	}
+
+	return ids, descriptions, nil
+}
+
+// updateEntity changes nothing if there is no such entity.
+func (s *Storage) updateEntity(ctx context.Context, kind string, id uuid.UUID, description string) error {
+	key := entityKey(kind, id)
+
+	return s.client.Watch(ctx, func(tx *goredis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil || exists == 0 {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.HSet(ctx, key, descriptionField, description)
+			return nil
+		})
+		return err
+	}, key)
+}
+
+// deleteUnlinkedEntity deletes the entity unless the set of its links is not empty. Links are only changed
+// while the entity is watched, so a concurrent link either fails or is seen here.
+func (s *Storage) deleteUnlinkedEntity(ctx context.Context, kind string, id uuid.UUID, linksKey string, errLinked error) error {
+	key := entityKey(kind, id)
+
+	return s.client.Watch(ctx, func(tx *goredis.Tx) error {
+		links, err := tx.SCard(ctx, linksKey).Result()
+		if err != nil {
+			return err
+		}
+		if links != 0 {
+			return errLinked
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.SRem(ctx, entitiesKey(kind), id.String())
+			return nil
+		})
+		return err
+	}, key, linksKey)
+}
+
+func (s *Storage) deleteEntity(ctx context.Context, kind string, id uuid.UUID) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, entityKey(kind, id))
+		pipe.SRem(ctx, entitiesKey(kind), id.String())
+		return nil
+	})
+	return err
+}
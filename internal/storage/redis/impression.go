@@ -0,0 +1,207 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const (
+	// impressionsKey is the sorted set of impression IDs, scored by when they were shown.
+	impressionsKey = "impressions"
+
+	impressionBannerField   = "banner_id"
+	impressionSlotField     = "slot_id"
+	impressionGroupField    = "group_id"
+	impressionShownAtField  = "shown_at"
+	impressionFeaturesField = "features"
+	// the times of the click and the conversion of the impression, in Unix nanoseconds
+	impressionClickedAtField   = "clicked_at"
+	impressionConvertedAtField = "converted_at"
+)
+
+// impressionRewardFields are the fields that mark the click and the conversion of an impression.
+var impressionRewardFields = map[string]string{
+	model.EventClick:      impressionClickedAtField,
+	model.EventConversion: impressionConvertedAtField,
+}
+
+// rewardImpressionScript marks the impression of a click or conversion unless it already has one. It does
+// not create the hashes of pruned impressions.
+var rewardImpressionScript = goredis.NewScript(`
+	if redis.call("EXISTS", KEYS[1]) == 0 then
+		return 0
+	end
+	return redis.call("HSETNX", KEYS[1], ARGV[1], ARGV[2])
+`)
+
+// impressionKey is the hash with the banner, slot, social group and show time of an impression.
+func impressionKey(id uuid.UUID) string {
+	return "impression:" + id.String()
+}
+
+func addImpression(ctx context.Context, pipe goredis.Pipeliner, event *model.Event) error {
+	values := []interface{}{
+		impressionBannerField, event.BannerID.String(),
+		impressionSlotField, event.SlotID.String(),
+		impressionGroupField, event.GroupID.String(),
+		impressionShownAtField, strconv.FormatInt(event.Timestamp.UnixNano(), 10),
+	}
+
+	if event.Features != nil {
+		features, err := json.Marshal(event.Features)
+		if err != nil {
+			return err
+		}
+		values = append(values, impressionFeaturesField, features)
+	}
+
+	pipe.HSet(ctx, impressionKey(event.ID), values...)
+	pipe.ZAddNX(ctx, impressionsKey, goredis.Z{
+		Score:  float64(event.Timestamp.UnixNano()),
+		Member: event.ID.String(),
+	})
+	return nil
+}
+
+// rewardImpression marks the impression the click or conversion names, if it names one.
+func rewardImpression(ctx context.Context, pipe goredis.Pipeliner, event *model.Event) {
+	if event.ImpressionID == nil {
+		return
+	}
+
+	rewardImpressionScript.Eval(ctx, pipe, []string{impressionKey(*event.ImpressionID)},
+		impressionRewardFields[event.Type], strconv.FormatInt(event.Timestamp.UnixNano(), 10))
+}
+
+// addRewardToStat adds the delta of the click or conversion, marks its impression and records the event
+// in the outbox in one transaction. The impression is watched, so concurrent replays cannot both count.
+func (s *Storage) addRewardToStat(ctx context.Context, delta *model.StatDelta, event *model.Event) error {
+	if event.ImpressionID == nil {
+		_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			s.addStatDelta(ctx, pipe, delta)
+			return addEventToOutbox(ctx, pipe, event)
+		})
+		return err
+	}
+
+	key := impressionKey(*event.ImpressionID)
+
+	return s.client.Watch(ctx, func(tx *goredis.Tx) error {
+		values, err := tx.HMGet(ctx, key, impressionShownAtField, impressionRewardFields[event.Type]).Result()
+		if err != nil {
+			return err
+		}
+		if values[0] == nil {
+			return errors.ErrImpressionNotFound
+		}
+		if values[1] != nil {
+			return errors.ErrImpressionAlreadyRewarded
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			s.addStatDelta(ctx, pipe, delta)
+			pipe.HSet(ctx, key, impressionRewardFields[event.Type], strconv.FormatInt(event.Timestamp.UnixNano(), 10))
+			return addEventToOutbox(ctx, pipe, event)
+		})
+		return err
+	}, key)
+}
+
+func (s *Storage) FindImpressionByID(ctx context.Context, impressionID *uuid.UUID) (*model.Impression, error) {
+	values, err := s.client.HGetAll(ctx, impressionKey(*impressionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	impression := &model.Impression{ID: *impressionID}
+
+	for field, id := range map[string]*uuid.UUID{
+		impressionBannerField: &impression.BannerID,
+		impressionSlotField:   &impression.SlotID,
+		impressionGroupField:  &impression.GroupID,
+	} {
+		*id, err = uuid.Parse(values[field])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	shownAt, err := strconv.ParseInt(values[impressionShownAtField], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	impression.ShownAt = time.Unix(0, shownAt).UTC()
+
+	for field, rewardedAt := range map[string]**time.Time{
+		impressionClickedAtField:   &impression.ClickedAt,
+		impressionConvertedAtField: &impression.ConvertedAt,
+	} {
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+
+		nanos, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		at := time.Unix(0, nanos).UTC()
+		*rewardedAt = &at
+	}
+
+	if features, ok := values[impressionFeaturesField]; ok {
+		err = json.Unmarshal([]byte(features), &impression.Features)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return impression, nil
+}
+
+// DeleteImpressionsBefore deletes the impressions shown before the time and returns how many there were.
+func (s *Storage) DeleteImpressionsBefore(ctx context.Context, before time.Time) (int, error) {
+	ids, err := s.client.ZRangeByScore(ctx, impressionsKey, &goredis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(before.UnixNano(), 10),
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	members := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		impressionID, err := uuid.Parse(id)
+		if err != nil {
+			return 0, err
+		}
+		keys = append(keys, impressionKey(impressionID))
+		members = append(members, id)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.ZRem(ctx, impressionsKey, members...)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	goredis "github.com/redis/go-redis/v9"
+	"time"
+)
+
+// RelayEvents passes up to limit of the oldest unsent events to publish and removes them
+// from the outbox if publish succeeds. Concurrent relays may publish the same event twice.
+func (s *Storage) RelayEvents(ctx context.Context, limit int, publish func(events []*model.Event) error) (int, error) {
+	ids, err := s.client.ZRange(ctx, outboxUnsentKey, 0, int64(limit)-1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	payloads, err := s.client.HMGet(ctx, outboxKey, ids...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	events := make([]*model.Event, 0, len(payloads))
+	for _, payload := range payloads {
+		if payload == nil {
+			continue
+		}
+
+		event := &model.Event{}
+		err = json.Unmarshal([]byte(payload.(string)), event)
+		if err != nil {
+			return 0, err
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 0 {
+		err = publish(events)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, outboxUnsentKey, members...)
+		pipe.HDel(ctx, outboxKey, ids...)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+// DeleteEventsSentBefore deletes nothing, relayed events are removed from the outbox right away.
+func (s *Storage) DeleteEventsSentBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,397 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AddBannerToSlot links the banner like AddBannersToSlots, so a concurrent delete of the banner or slot
+// either fails or sees the link.
+func (s *Storage) AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	results, err := s.AddBannersToSlots(ctx, []*model.BannerSlot{{BannerID: *bannerID, SlotID: *slotID}})
+	if err != nil {
+		return err
+	}
+
+	return results[0]
+}
+
+func (s *Storage) FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUID) (*model.BannerSlot, error) {
+	linked, err := s.client.SIsMember(ctx, slotBannersKey(*slotID), bannerID.String()).Result()
+	if err != nil || !linked {
+		return nil, err
+	}
+
+	return &model.BannerSlot{
+		BannerID: *bannerID,
+		SlotID:   *slotID,
+	}, nil
+}
+
+func (s *Storage) RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.SRem(ctx, slotBannersKey(*slotID), bannerID.String())
+		pipe.SRem(ctx, bannerSlotsKey(*bannerID), slotID.String())
+		return nil
+	})
+	return err
+}
+
+func (s *Storage) FindBannersInSlot(ctx context.Context, slotID *uuid.UUID) ([]*uuid.UUID, error) {
+	return s.findIDs(ctx, slotBannersKey(*slotID))
+}
+
+func (s *Storage) FindSlotsOfBanner(ctx context.Context, bannerID *uuid.UUID) ([]*uuid.UUID, error) {
+	return s.findIDs(ctx, bannerSlotsKey(*bannerID))
+}
+
+func (s *Storage) findIDs(ctx context.Context, key string) ([]*uuid.UUID, error) {
+	members, err := s.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(members)
+
+	ids := make([]*uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, &id)
+	}
+
+	return ids, nil
+}
+
+func (s *Storage) FindStatByParams(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) (*model.Stat, error) {
+	values, err := s.client.HMGet(ctx, statKey(*slotID, *socialGroupID),
+		showsField(*bannerID), clicksField(*bannerID), conversionsField(*bannerID), revenueField(*bannerID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if values[0] == nil && values[1] == nil && values[2] == nil && values[3] == nil {
+		return nil, nil
+	}
+
+	stat := &model.Stat{
+		BannerID: *bannerID,
+		SlotID:   *slotID,
+		GroupID:  *socialGroupID,
+	}
+
+	if stat.Shows, err = parseCounter(values[0]); err != nil {
+		return nil, err
+	}
+	if stat.Clicks, err = parseCounter(values[1]); err != nil {
+		return nil, err
+	}
+	if stat.Conversions, err = parseCounter(values[2]); err != nil {
+		return nil, err
+	}
+	if values[3] != nil {
+		if stat.Revenue, err = strconv.ParseFloat(values[3].(string), 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return stat, nil
+}
+
+func parseCounter(value interface{}) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+	return strconv.Atoi(value.(string))
+}
+
+// AddClickToStat counts the click, marks its impression and records the event in the outbox in one transaction.
+// It fails with ErrImpressionAlreadyRewarded if the impression was clicked.
+func (s *Storage) AddClickToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	return s.addRewardToStat(ctx, &model.StatDelta{
+		BannerID: stat.BannerID,
+		SlotID:   stat.SlotID,
+		GroupID:  stat.GroupID,
+		Bucket:   event.Timestamp,
+		Clicks:   1,
+	}, event)
+}
+
+// AddConversionToStat counts the conversion with the value of the event, marks its impression and records
+// the event in the outbox in one transaction. It fails with ErrImpressionAlreadyRewarded if the impression
+// converted.
+func (s *Storage) AddConversionToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	return s.addRewardToStat(ctx, &model.StatDelta{
+		BannerID:    stat.BannerID,
+		SlotID:      stat.SlotID,
+		GroupID:     stat.GroupID,
+		Bucket:      event.Timestamp,
+		Conversions: 1,
+		Revenue:     event.Value,
+	}, event)
+}
+
+// AddShowToStat counts the show and records the event in the outbox and its impression in one transaction.
+func (s *Storage) AddShowToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		s.addStatDelta(ctx, pipe, &model.StatDelta{
+			BannerID: stat.BannerID,
+			SlotID:   stat.SlotID,
+			GroupID:  stat.GroupID,
+			Bucket:   event.Timestamp,
+			Shows:    1,
+		})
+		err := addImpression(ctx, pipe, event)
+		if err != nil {
+			return err
+		}
+		return addEventToOutbox(ctx, pipe, event)
+	})
+	return err
+}
+
+// AddStatDeltas adds the deltas to the statistics, records the events in the outbox, the impressions
+// of the show events and the clicks and conversions of the impressions in one transaction.
+func (s *Storage) AddStatDeltas(ctx context.Context, deltas []*model.StatDelta, events []*model.Event) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, delta := range deltas {
+			s.addStatDelta(ctx, pipe, delta)
+		}
+
+		for _, event := range events {
+			err := addEventToOutbox(ctx, pipe, event)
+			if err != nil {
+				return err
+			}
+
+			if event.Type == model.EventShow {
+				err = addImpression(ctx, pipe, event)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			// the deltas already count the reward, the cache rejects the ones the impression has
+			rewardImpression(ctx, pipe, event)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *Storage) addStatDelta(ctx context.Context, pipe goredis.Pipeliner, delta *model.StatDelta) {
+	bucket := hourBucket(delta.Bucket)
+
+	pipe.SAdd(ctx, statsKey, statPair(delta.SlotID, delta.GroupID))
+	pipe.ZAdd(ctx, statBucketsKey(delta.SlotID, delta.GroupID), goredis.Z{
+		Score:  float64(bucket),
+		Member: strconv.FormatInt(bucket, 10),
+	})
+
+	bucketKey := statBucketKey(delta.SlotID, delta.GroupID, bucket)
+	for _, key := range []string{statKey(delta.SlotID, delta.GroupID), bucketKey} {
+		pipe.HIncrBy(ctx, key, showsField(delta.BannerID), int64(delta.Shows))
+		pipe.HIncrBy(ctx, key, clicksField(delta.BannerID), int64(delta.Clicks))
+
+		// most banners never convert, so their hashes do not get the fields
+		if delta.Conversions != 0 || delta.Revenue != 0 {
+			pipe.HIncrBy(ctx, key, conversionsField(delta.BannerID), int64(delta.Conversions))
+			pipe.HIncrByFloat(ctx, key, revenueField(delta.BannerID), delta.Revenue)
+		}
+	}
+
+	// the key exists now, so it can expire
+	if s.bucketRetention > 0 {
+		expiresAt := time.Unix(bucket, 0).Add(time.Hour + s.bucketRetention)
+		pipe.ExpireAt(ctx, bucketKey, expiresAt)
+		pipe.ZRemRangeByScore(ctx, statBucketsKey(delta.SlotID, delta.GroupID),
+			"-inf", "("+strconv.FormatInt(time.Now().Add(-time.Hour-s.bucketRetention).Unix(), 10))
+	}
+}
+
+func (s *Storage) FindStatsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID) ([]*model.Stat, error) {
+	values, err := s.client.HGetAll(ctx, statKey(*slotID, *socialGroupID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStats(values, *slotID, *socialGroupID)
+}
+
+// parseStats reads a hash with "banner:shows", "banner:clicks", "banner:conversions" and "banner:revenue"
+// fields, ordered by banner ID.
+func parseStats(values map[string]string, slotID, socialGroupID uuid.UUID) ([]*model.Stat, error) {
+	byBanner := make(map[uuid.UUID]*model.Stat)
+
+	for field, value := range values {
+		separator := strings.LastIndex(field, ":")
+		if separator < 0 {
+			continue
+		}
+
+		bannerID, err := uuid.Parse(field[:separator])
+		if err != nil {
+			return nil, err
+		}
+
+		stat, ok := byBanner[bannerID]
+		if !ok {
+			stat = &model.Stat{
+				BannerID: bannerID,
+				SlotID:   slotID,
+				GroupID:  socialGroupID,
+			}
+			byBanner[bannerID] = stat
+		}
+
+		switch field[separator+1:] {
+		case "shows":
+			stat.Shows, err = strconv.Atoi(value)
+		case "clicks":
+			stat.Clicks, err = strconv.Atoi(value)
+		case "conversions":
+			stat.Conversions, err = strconv.Atoi(value)
+		case "revenue":
+			stat.Revenue, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats := make([]*model.Stat, 0, len(byBanner))
+	for _, stat := range byBanner {
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].BannerID.String() < stats[j].BannerID.String()
+	})
+
+	return stats, nil
+}
+
+func (s *Storage) FindStatBucketsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID, since time.Time) ([]*model.StatBucket, error) {
+	members, err := s.client.ZRangeByScore(ctx, statBucketsKey(*slotID, *socialGroupID), &goredis.ZRangeBy{
+		Min: strconv.FormatInt(since.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	bucketStarts := make([]int64, len(members))
+	commands := make([]*goredis.MapStringStringCmd, len(members))
+
+	_, err = s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, member := range members {
+			bucketStarts[i], err = strconv.ParseInt(member, 10, 64)
+			if err != nil {
+				return err
+			}
+			commands[i] = pipe.HGetAll(ctx, statBucketKey(*slotID, *socialGroupID, bucketStarts[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []*model.StatBucket
+
+	for i, command := range commands {
+		stats, err := parseStats(command.Val(), *slotID, *socialGroupID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stat := range stats {
+			buckets = append(buckets, &model.StatBucket{
+				BannerID: stat.BannerID,
+				SlotID:   stat.SlotID,
+				GroupID:  stat.GroupID,
+				Bucket:   time.Unix(bucketStarts[i], 0).UTC(),
+				Shows:    stat.Shows,
+				Clicks:   stat.Clicks,
+
+				Conversions: stat.Conversions,
+				Revenue:     stat.Revenue,
+			})
+		}
+	}
+
+	return buckets, nil
+}
+
+// deleteStats deletes the statistics of a banner, slot or social group.
+func (s *Storage) deleteStats(ctx context.Context, kind string, id uuid.UUID) error {
+	pairs, err := s.client.SMembers(ctx, statsKey).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range pairs {
+		slotID, socialGroupID, err := parseStatPair(pair)
+		if err != nil {
+			return err
+		}
+
+		if kind == slotKind && slotID != id || kind == socialGroupKind && socialGroupID != id {
+			continue
+		}
+
+		buckets, err := s.client.ZRange(ctx, statBucketsKey(slotID, socialGroupID), 0, -1).Result()
+		if err != nil {
+			return err
+		}
+
+		keys := []string{statKey(slotID, socialGroupID)}
+		for _, member := range buckets {
+			bucket, err := strconv.ParseInt(member, 10, 64)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, statBucketKey(slotID, socialGroupID, bucket))
+		}
+
+		_, err = s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			if kind == bannerKind {
+				for _, key := range keys {
+					pipe.HDel(ctx, key, showsField(id), clicksField(id), conversionsField(id), revenueField(id))
+				}
+				return nil
+			}
+
+			pipe.Del(ctx, append(keys, statBucketsKey(slotID, socialGroupID))...)
+			pipe.SRem(ctx, statsKey, pair)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addEventToOutbox(ctx context.Context, pipe goredis.Pipeliner, event *model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	pipe.HSetNX(ctx, outboxKey, event.ID.String(), payload)
+	pipe.ZAddNX(ctx, outboxUnsentKey, goredis.Z{
+		Score:  float64(event.Timestamp.UnixNano()),
+		Member: event.ID.String(),
+	})
+	return nil
+}
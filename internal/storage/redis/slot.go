@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateSlot(ctx context.Context, slot *model.Slot) error {
+	id, err := s.createEntity(ctx, slotKind, slot.Description)
+	if err != nil {
+		return err
+	}
+
+	slot.ID = id
+	return nil
+}
+
+func (s *Storage) FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error) {
+	description, ok, err := s.findEntity(ctx, slotKind, *slotID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &model.Slot{
+		ID:          *slotID,
+		Description: description,
+	}, nil
+}
+
+func (s *Storage) FindSlots(ctx context.Context) ([]*model.Slot, error) {
+	ids, descriptions, err := s.findEntities(ctx, slotKind)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]*model.Slot, len(ids))
+	for i := range ids {
+		slots[i] = &model.Slot{
+			ID:          ids[i],
+			Description: descriptions[i],
+		}
+	}
+
+	return slots, nil
+}
+
+func (s *Storage) UpdateSlot(ctx context.Context, slot *model.Slot) error {
+	return s.updateEntity(ctx, slotKind, slot.ID, slot.Description)
+}
+
+// DeleteSlot deletes the slot together with its statistics. It fails if the slot has linked banners.
+func (s *Storage) DeleteSlot(ctx context.Context, slotID *uuid.UUID) error {
+	err := s.deleteUnlinkedEntity(ctx, slotKind, *slotID, slotBannersKey(*slotID), errors.ErrSlotHasBanners)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteStats(ctx, slotKind, *slotID)
+}
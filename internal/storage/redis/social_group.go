@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	id, err := s.createEntity(ctx, socialGroupKind, socialGroup.Description)
+	if err != nil {
+		return err
+	}
+
+	socialGroup.ID = id
+	return nil
+}
+
+func (s *Storage) FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error) {
+	description, ok, err := s.findEntity(ctx, socialGroupKind, *socialGroupID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &model.Group{
+		ID:          *socialGroupID,
+		Description: description,
+	}, nil
+}
+
+func (s *Storage) FindSocialGroups(ctx context.Context) ([]*model.Group, error) {
+	ids, descriptions, err := s.findEntities(ctx, socialGroupKind)
+	if err != nil {
+		return nil, err
+	}
+
+	socialGroups := make([]*model.Group, len(ids))
+	for i := range ids {
+		socialGroups[i] = &model.Group{
+			ID:          ids[i],
+			Description: descriptions[i],
+		}
+	}
+
+	return socialGroups, nil
+}
+
+func (s *Storage) UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	return s.updateEntity(ctx, socialGroupKind, socialGroup.ID, socialGroup.Description)
+}
+
+// DeleteSocialGroup deletes the social group together with its statistics.
+func (s *Storage) DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error {
+	err := s.deleteStats(ctx, socialGroupKind, *socialGroupID)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteEntity(ctx, socialGroupKind, *socialGroupID)
+}
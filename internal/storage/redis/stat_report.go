@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"sort"
+)
+
+type reportKey struct {
+	bannerID      uuid.UUID
+	slotID        uuid.UUID
+	socialGroupID uuid.UUID
+}
+
+// FindStatReports aggregates the statistics in memory, since Redis cannot group them.
+func (s *Storage) FindStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error) {
+	var byBanner, bySlot, byGroup bool
+	for _, dimension := range filter.GroupBy {
+		switch dimension {
+		case model.DimensionBanner:
+			byBanner = true
+		case model.DimensionSlot:
+			bySlot = true
+		case model.DimensionGroup:
+			byGroup = true
+		default:
+			return nil, fmt.Errorf("unknown dimension %q", dimension)
+		}
+	}
+
+	pairs, err := s.client.SMembers(ctx, statsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make(map[reportKey]*model.StatReport)
+	// without dimensions everything is summed up into one row, even if there are no statistics
+	if !byBanner && !bySlot && !byGroup {
+		reports[reportKey{}] = &model.StatReport{}
+	}
+
+	for _, pair := range pairs {
+		slotID, socialGroupID, err := parseStatPair(pair)
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.SlotID != nil && *filter.SlotID != slotID || filter.GroupID != nil && *filter.GroupID != socialGroupID {
+			continue
+		}
+
+		values, err := s.client.HGetAll(ctx, statKey(slotID, socialGroupID)).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		stats, err := parseStats(values, slotID, socialGroupID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stat := range stats {
+			if filter.BannerID != nil && *filter.BannerID != stat.BannerID {
+				continue
+			}
+
+			key := reportKey{}
+			if byBanner {
+				key.bannerID = stat.BannerID
+			}
+			if bySlot {
+				key.slotID = stat.SlotID
+			}
+			if byGroup {
+				key.socialGroupID = stat.GroupID
+			}
+
+			report, ok := reports[key]
+			if !ok {
+				report = &model.StatReport{}
+				if byBanner {
+					report.BannerID = &key.bannerID
+				}
+				if bySlot {
+					report.SlotID = &key.slotID
+				}
+				if byGroup {
+					report.GroupID = &key.socialGroupID
+				}
+				reports[key] = report
+			}
+
+			report.Shows += int64(stat.Shows)
+			report.Clicks += int64(stat.Clicks)
+			report.Conversions += int64(stat.Conversions)
+			report.Revenue += stat.Revenue
+		}
+	}
+
+	result := make([]*model.StatReport, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, report)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return reportOrder(filter.GroupBy, result[i]) < reportOrder(filter.GroupBy, result[j])
+	})
+
+	return result, nil
+}
+
+// reportOrder orders the reports by their dimensions in the order they were requested.
+func reportOrder(groupBy []string, report *model.StatReport) string {
+	var order string
+	for _, dimension := range groupBy {
+		switch dimension {
+		case model.DimensionBanner:
+			order += report.BannerID.String()
+		case model.DimensionSlot:
+			order += report.SlotID.String()
+		case model.DimensionGroup:
+			order += report.GroupID.String()
+		}
+	}
+	return order
+}
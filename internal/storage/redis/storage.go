@@ -0,0 +1,119 @@
+// Package redis stores banners, slots, social groups and their statistics in Redis hashes and sets.
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	bannerKind      = "banner"
+	slotKind        = "slot"
+	socialGroupKind = "social_group"
+
+	descriptionField = "description"
+
+	// statsKey is the set of "slot:social group" pairs that have statistics.
+	statsKey = "stats"
+
+	outboxKey       = "outbox"
+	outboxUnsentKey = "outbox:unsent"
+)
+
+type Storage struct {
+	client goredis.UniversalClient
+	// bucketRetention is how long the hourly buckets are kept after they end, zero for ever.
+	bucketRetention time.Duration
+}
+
+func NewStorage(client goredis.UniversalClient, bucketRetention time.Duration) *Storage {
+	return &Storage{
+		client:          client,
+		bucketRetention: bucketRetention,
+	}
+}
+
+// Ping checks that Redis can be reached.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// entityKey is the hash with the description of a banner, slot or social group.
+func entityKey(kind string, id uuid.UUID) string {
+	return kind + ":" + id.String()
+}
+
+// entitiesKey is the set of IDs of all banners, slots or social groups.
+func entitiesKey(kind string) string {
+	return kind + "s"
+}
+
+func slotBannersKey(slotID uuid.UUID) string {
+	return "slot_banners:" + slotID.String()
+}
+
+func bannerSlotsKey(bannerID uuid.UUID) string {
+	return "banner_slots:" + bannerID.String()
+}
+
+func statPair(slotID, socialGroupID uuid.UUID) string {
+	return slotID.String() + ":" + socialGroupID.String()
+}
+
+func parseStatPair(pair string) (slotID, socialGroupID uuid.UUID, err error) {
+	parts := strings.Split(pair, ":")
+	if len(parts) != 2 {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid stat pair %q", pair)
+	}
+
+	slotID, err = uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	socialGroupID, err = uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return slotID, socialGroupID, nil
+}
+
+// statKey is the hash with the shows, clicks, conversions and revenue of every banner of a slot in a social group.
+func statKey(slotID, socialGroupID uuid.UUID) string {
+	return "stat:" + statPair(slotID, socialGroupID)
+}
+
+// statBucketsKey is the sorted set of the hourly buckets of a slot in a social group, scored by their start.
+func statBucketsKey(slotID, socialGroupID uuid.UUID) string {
+	return "stat_buckets:" + statPair(slotID, socialGroupID)
+}
+
+func statBucketKey(slotID, socialGroupID uuid.UUID, bucket int64) string {
+	return "stat_bucket:" + statPair(slotID, socialGroupID) + ":" + strconv.FormatInt(bucket, 10)
+}
+
+func showsField(bannerID uuid.UUID) string {
+	return bannerID.String() + ":shows"
+}
+
+func clicksField(bannerID uuid.UUID) string {
+	return bannerID.String() + ":clicks"
+}
+
+func conversionsField(bannerID uuid.UUID) string {
+	return bannerID.String() + ":conversions"
+}
+
+func revenueField(bannerID uuid.UUID) string {
+	return bannerID.String() + ":revenue"
+}
+
+func hourBucket(t time.Time) int64 {
+	return t.Truncate(time.Hour).Unix()
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/storage/storagetest"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		mr := miniredis.RunT(t)
+		return NewStorage(goredis.NewClient(&goredis.Options{Addr: mr.Addr()}), 24*time.Hour)
+	})
+}
+
+func TestBucketRetention(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	s := NewStorage(goredis.NewClient(&goredis.Options{Addr: mr.Addr()}), time.Hour)
+
+	delta := &model.StatDelta{
+		BannerID: uuid.New(),
+		SlotID:   uuid.New(),
+		GroupID:  uuid.New(),
+		Bucket:   time.Now().Truncate(time.Hour),
+		Shows:    1,
+	}
+	require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{delta}, nil))
+
+	bucketKey := statBucketKey(delta.SlotID, delta.GroupID, hourBucket(delta.Bucket))
+	require.True(t, mr.Exists(bucketKey))
+	require.Positive(t, mr.TTL(bucketKey))
+
+	buckets, err := s.FindStatBucketsBySlotAndSocialGroup(ctx, &delta.SlotID, &delta.GroupID, delta.Bucket)
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+
+	mr.FastForward(3 * time.Hour)
+	require.False(t, mr.Exists(bucketKey), "buckets expire once they are older than the retention")
+
+	stats, err := s.FindStatsBySlotAndSocialGroup(ctx, &delta.SlotID, &delta.GroupID)
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "lifetime statistics do not expire")
+}
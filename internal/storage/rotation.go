@@ -2,38 +2,79 @@ package storage
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	stdErrors "errors"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/metrics"
 	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/aakosarev/banner-rotation/internal/tracing"
 	"github.com/aakosarev/banner-rotation/pkg/client/postgresql"
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"log/slog"
+	"time"
 )
 
 type Storage struct {
 	client postgresql.Client
+	logger *slog.Logger
 }
 
-func NewStorage(client postgresql.Client) *Storage {
+func NewStorage(client postgresql.Client, logger *slog.Logger) *Storage {
 	return &Storage{
 		client: client,
+		logger: logger,
 	}
 }
 
+// observe times a query of method in the metrics, traces it in a span and logs it at the debug level.
+func (s *Storage) observe(ctx context.Context, method string) (context.Context, func()) {
+	start := time.Now()
+	observeDuration := metrics.ObserveStorage(method)
+	ctx, span := tracing.Start(ctx, "Storage."+method, semconv.DBSystemPostgreSQL)
+
+	return ctx, func() {
+		span.End()
+		observeDuration()
+		s.logger.DebugContext(ctx, "storage query", slog.String("method", method), slog.Duration("latency", time.Since(start)))
+	}
+}
+
+// Ping checks that the database can be reached.
+func (s *Storage) Ping(ctx context.Context) error {
+	ctx, done := s.observe(ctx, "Ping")
+	defer done()
+
+	return s.client.Ping(ctx)
+}
+
 func (s *Storage) AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	ctx, done := s.observe(ctx, "AddBannerToSlot")
+	defer done()
+
 	query := `
 		INSERT INTO banner_slot(banner_id, slot_id)
-		VALUES ($1, $2);
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
 	`
-	_, err := s.client.Exec(ctx, query, bannerID, slotID)
+	tag, err := s.client.Exec(ctx, query, bannerID, slotID)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return errors.ErrBannerAlreadyLinkedToSlot
+	}
+
 	return nil
 }
 
 func (s *Storage) FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUID) (*model.BannerSlot, error) {
+	ctx, done := s.observe(ctx, "FindBannerSlot")
+	defer done()
+
 	query := `
 		SELECT banner_id, slot_id
 		FROM banner_slot
@@ -44,7 +85,7 @@ func (s *Storage) FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUI
 
 	err := pgxscan.Get(ctx, s.client, &bannerSlot, query, bannerID, slotID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if stdErrors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
@@ -54,6 +95,9 @@ func (s *Storage) FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUI
 }
 
 func (s *Storage) RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error {
+	ctx, done := s.observe(ctx, "RemoveBannerFromSlot")
+	defer done()
+
 	query := `
 		DELETE FROM banner_slot
 		WHERE banner_id = $1 AND slot_id = $2
@@ -67,8 +111,11 @@ func (s *Storage) RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uu
 }
 
 func (s *Storage) FindStatByParams(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) (*model.Stat, error) {
+	ctx, done := s.observe(ctx, "FindStatByParams")
+	defer done()
+
 	query := `
-		SELECT banner_id, slot_id, social_group_id, shows, clicks
+		SELECT banner_id, slot_id, social_group_id, shows, clicks, conversions, revenue
 		FROM stat
 		WHERE banner_id = $1 AND slot_id = $2 AND social_group_id = $3
 	`
@@ -77,7 +124,7 @@ func (s *Storage) FindStatByParams(ctx context.Context, bannerID, slotID, social
 
 	err := pgxscan.Get(ctx, s.client, &stat, query, bannerID, slotID, socialGroupID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if stdErrors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
@@ -86,52 +133,133 @@ func (s *Storage) FindStatByParams(ctx context.Context, bannerID, slotID, social
 	return &stat, nil
 }
 
-func (s *Storage) CreateStat(ctx context.Context, stat *model.Stat) error {
-	query := `
-		INSERT INTO stat(banner_id, slot_id, social_group_id, shows, clicks)
-		VALUES ($1, $2, $3, $4, $5);
-	`
-	_, err := s.client.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID, stat.Shows, stat.Clicks)
-	if err != nil {
-		return err
-	}
+// AddClickToStat counts the click, creating the stat if needed, marks its impression and records the event
+// in the outbox in one transaction. It fails with ErrImpressionAlreadyRewarded if the impression was clicked.
+func (s *Storage) AddClickToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	ctx, done := s.observe(ctx, "AddClickToStat")
+	defer done()
 
-	return nil
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		err := rewardImpression(ctx, tx, event)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO stat(banner_id, slot_id, social_group_id, shows, clicks)
+			VALUES ($1, $2, $3, 0, 1)
+			ON CONFLICT (banner_id, slot_id, social_group_id)
+			DO UPDATE SET clicks = stat.clicks + 1
+		`
+
+		_, err = tx.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO stat_bucket(banner_id, slot_id, social_group_id, bucket, shows, clicks)
+			VALUES ($1, $2, $3, date_trunc('hour', now()), 0, 1)
+			ON CONFLICT (slot_id, social_group_id, bucket, banner_id)
+			DO UPDATE SET clicks = stat_bucket.clicks + 1
+		`
+
+		_, err = tx.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID)
+		if err != nil {
+			return err
+		}
+
+		return addEventToOutbox(ctx, tx, event)
+	})
 }
 
-func (s *Storage) AddClickToStat(ctx context.Context, stat *model.Stat) error {
-	query := `
-		UPDATE stat
-		SET clicks = clicks + 1
-		WHERE banner_id = $1 AND slot_id = $2 AND social_group_id = $3
-	`
+// AddConversionToStat counts the conversion with the value of the event, creating the stat if needed, marks
+// its impression and records the event in the outbox in one transaction. It fails with
+// ErrImpressionAlreadyRewarded if the impression converted.
+func (s *Storage) AddConversionToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	ctx, done := s.observe(ctx, "AddConversionToStat")
+	defer done()
+
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		err := rewardImpression(ctx, tx, event)
+		if err != nil {
+			return err
+		}
 
-	_, err := s.client.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID)
-	if err != nil {
-		return err
-	}
+		query := `
+			INSERT INTO stat(banner_id, slot_id, social_group_id, shows, clicks, conversions, revenue)
+			VALUES ($1, $2, $3, 0, 0, 1, $4)
+			ON CONFLICT (banner_id, slot_id, social_group_id)
+			DO UPDATE SET conversions = stat.conversions + 1, revenue = stat.revenue + $4
+		`
 
-	return nil
+		_, err = tx.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID, event.Value)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO stat_bucket(banner_id, slot_id, social_group_id, bucket, shows, clicks, conversions, revenue)
+			VALUES ($1, $2, $3, date_trunc('hour', now()), 0, 0, 1, $4)
+			ON CONFLICT (slot_id, social_group_id, bucket, banner_id)
+			DO UPDATE SET conversions = stat_bucket.conversions + 1, revenue = stat_bucket.revenue + $4
+		`
+
+		_, err = tx.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID, event.Value)
+		if err != nil {
+			return err
+		}
+
+		return addEventToOutbox(ctx, tx, event)
+	})
 }
 
-func (s *Storage) AddShowToStat(ctx context.Context, stat *model.Stat) error {
-	query := `
-		UPDATE stat
-		SET shows = shows + 1
-		WHERE banner_id = $1 AND slot_id = $2 AND social_group_id = $3
-	`
+// AddShowToStat counts the show, creating the stat if needed, and records the event in the outbox and its impression
+// in one transaction.
+func (s *Storage) AddShowToStat(ctx context.Context, stat *model.Stat, event *model.Event) error {
+	ctx, done := s.observe(ctx, "AddShowToStat")
+	defer done()
+
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO stat(banner_id, slot_id, social_group_id, shows, clicks)
+			VALUES ($1, $2, $3, 1, 0)
+			ON CONFLICT (banner_id, slot_id, social_group_id)
+			DO UPDATE SET shows = stat.shows + 1
+		`
+
+		_, err := tx.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID)
+		if err != nil {
+			return err
+		}
 
-	_, err := s.client.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID)
-	if err != nil {
-		return err
-	}
+		query = `
+			INSERT INTO stat_bucket(banner_id, slot_id, social_group_id, bucket, shows, clicks)
+			VALUES ($1, $2, $3, date_trunc('hour', now()), 1, 0)
+			ON CONFLICT (slot_id, social_group_id, bucket, banner_id)
+			DO UPDATE SET shows = stat_bucket.shows + 1
+		`
 
-	return nil
+		_, err = tx.Exec(ctx, query, stat.BannerID, stat.SlotID, stat.GroupID)
+		if err != nil {
+			return err
+		}
+
+		err = addImpression(ctx, tx, event)
+		if err != nil {
+			return err
+		}
+
+		return addEventToOutbox(ctx, tx, event)
+	})
 }
 
 func (s *Storage) FindStatsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID) ([]*model.Stat, error) {
+	ctx, done := s.observe(ctx, "FindStatsBySlotAndSocialGroup")
+	defer done()
+
 	query := `
-		SELECT banner_id, slot_id, social_group_id, shows, clicks
+		SELECT banner_id, slot_id, social_group_id, shows, clicks, conversions, revenue
 		FROM stat
 		WHERE slot_id = $1 AND social_group_id = $2
 	`
@@ -147,6 +275,9 @@ func (s *Storage) FindStatsBySlotAndSocialGroup(ctx context.Context, slotID, soc
 }
 
 func (s *Storage) FindBannersInSlot(ctx context.Context, slotID *uuid.UUID) ([]*uuid.UUID, error) {
+	ctx, done := s.observe(ctx, "FindBannersInSlot")
+	defer done()
+
 	query := `
 		SELECT banner_id
 		FROM banner_slot
@@ -164,6 +295,9 @@ func (s *Storage) FindBannersInSlot(ctx context.Context, slotID *uuid.UUID) ([]*
 }
 
 func (s *Storage) FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error) {
+	ctx, done := s.observe(ctx, "FindBannerByID")
+	defer done()
+
 	query := `
 		SELECT id, description
 		FROM banner
@@ -173,7 +307,7 @@ func (s *Storage) FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*mod
 
 	err := pgxscan.Get(ctx, s.client, &banner, query, bannerID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if stdErrors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
@@ -183,6 +317,9 @@ func (s *Storage) FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*mod
 }
 
 func (s *Storage) FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error) {
+	ctx, done := s.observe(ctx, "FindSlotByID")
+	defer done()
+
 	query := `
 		SELECT id, description
 		FROM slot
@@ -193,7 +330,7 @@ func (s *Storage) FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.S
 
 	err := pgxscan.Get(ctx, s.client, &slot, query, slotID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if stdErrors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
@@ -203,6 +340,9 @@ func (s *Storage) FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.S
 }
 
 func (s *Storage) FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error) {
+	ctx, done := s.observe(ctx, "FindSocialGroupByID")
+	defer done()
+
 	query := `
 		SELECT id, description
 		FROM social_group
@@ -213,7 +353,7 @@ func (s *Storage) FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.U
 
 	err := pgxscan.Get(ctx, s.client, &socialGroup, query, socialGroupID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if stdErrors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
@@ -221,3 +361,103 @@ func (s *Storage) FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.U
 
 	return &socialGroup, nil
 }
+
+func (s *Storage) FindStatBucketsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID, since time.Time) ([]*model.StatBucket, error) {
+	ctx, done := s.observe(ctx, "FindStatBucketsBySlotAndSocialGroup")
+	defer done()
+
+	query := `
+		SELECT banner_id, slot_id, social_group_id, bucket, shows, clicks, conversions, revenue
+		FROM stat_bucket
+		WHERE slot_id = $1 AND social_group_id = $2 AND bucket >= $3
+	`
+
+	var buckets []*model.StatBucket
+
+	err := pgxscan.Select(ctx, s.client, &buckets, query, slotID, socialGroupID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func (s *Storage) FindSlotsOfBanner(ctx context.Context, bannerID *uuid.UUID) ([]*uuid.UUID, error) {
+	ctx, done := s.observe(ctx, "FindSlotsOfBanner")
+	defer done()
+
+	query := `
+		SELECT slot_id
+		FROM banner_slot
+		WHERE banner_id = $1
+	`
+
+	var slotIDs []*uuid.UUID
+
+	err := pgxscan.Select(ctx, s.client, &slotIDs, query, bannerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return slotIDs, nil
+}
+
+// AddStatDeltas adds the deltas to the statistics, records the events in the outbox, the impressions
+// of the show events and the clicks and conversions of the impressions in one transaction.
+func (s *Storage) AddStatDeltas(ctx context.Context, deltas []*model.StatDelta, events []*model.Event) error {
+	ctx, done := s.observe(ctx, "AddStatDeltas")
+	defer done()
+
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		statQuery := `
+			INSERT INTO stat(banner_id, slot_id, social_group_id, shows, clicks, conversions, revenue)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (banner_id, slot_id, social_group_id)
+			DO UPDATE SET shows = stat.shows + $4, clicks = stat.clicks + $5,
+				conversions = stat.conversions + $6, revenue = stat.revenue + $7
+		`
+		bucketQuery := `
+			INSERT INTO stat_bucket(banner_id, slot_id, social_group_id, bucket, shows, clicks, conversions, revenue)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (slot_id, social_group_id, bucket, banner_id)
+			DO UPDATE SET shows = stat_bucket.shows + $5, clicks = stat_bucket.clicks + $6,
+				conversions = stat_bucket.conversions + $7, revenue = stat_bucket.revenue + $8
+		`
+
+		batch := &pgx.Batch{}
+		for _, delta := range deltas {
+			batch.Queue(statQuery, delta.BannerID, delta.SlotID, delta.GroupID,
+				delta.Shows, delta.Clicks, delta.Conversions, delta.Revenue)
+			batch.Queue(bucketQuery, delta.BannerID, delta.SlotID, delta.GroupID, delta.Bucket,
+				delta.Shows, delta.Clicks, delta.Conversions, delta.Revenue)
+		}
+
+		for _, event := range events {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			batch.Queue(addEventToOutboxQuery, event.ID, payload)
+
+			switch {
+			case event.Type == model.EventShow:
+				batch.Queue(addImpressionQuery, event.ID, event.BannerID, event.SlotID, event.GroupID, event.Timestamp,
+					event.Features)
+			case event.ImpressionID != nil:
+				// the deltas already count the reward, the cache rejects the ones the impression has
+				batch.Queue(rewardImpressionQueries[event.Type], event.ImpressionID, event.Timestamp)
+			}
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		for i := 0; i < batch.Len(); i++ {
+			_, err := results.Exec()
+			if err != nil {
+				results.Close()
+				return err
+			}
+		}
+
+		return results.Close()
+	})
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+func (s *Storage) CreateSlot(ctx context.Context, slot *model.Slot) error {
+	ctx, done := s.observe(ctx, "CreateSlot")
+	defer done()
+
+	query := `
+		INSERT INTO slot(description)
+		VALUES ($1)
+		RETURNING id
+	`
+
+	err := s.client.QueryRow(ctx, query, slot.Description).Scan(&slot.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) FindSlots(ctx context.Context) ([]*model.Slot, error) {
+	ctx, done := s.observe(ctx, "FindSlots")
+	defer done()
+
+	query := `
+		SELECT id, description
+		FROM slot
+		ORDER BY id
+	`
+
+	var slots []*model.Slot
+
+	err := pgxscan.Select(ctx, s.client, &slots, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+func (s *Storage) UpdateSlot(ctx context.Context, slot *model.Slot) error {
+	ctx, done := s.observe(ctx, "UpdateSlot")
+	defer done()
+
+	query := `
+		UPDATE slot
+		SET description = $2
+		WHERE id = $1
+	`
+
+	_, err := s.client.Exec(ctx, query, slot.ID, slot.Description)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSlot deletes the slot together with its statistics. It fails if the slot has linked banners.
+func (s *Storage) DeleteSlot(ctx context.Context, slotID *uuid.UUID) error {
+	ctx, done := s.observe(ctx, "DeleteSlot")
+	defer done()
+
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		// FOR UPDATE waits for the transactions that link the slot, they hold it FOR SHARE
+		_, err := tx.Exec(ctx, `SELECT id FROM slot WHERE id = $1 FOR UPDATE`, slotID)
+		if err != nil {
+			return err
+		}
+
+		var linked bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM banner_slot WHERE slot_id = $1)`, slotID).Scan(&linked)
+		if err != nil {
+			return err
+		}
+		if linked {
+			return errors.ErrSlotHasBanners
+		}
+
+		queries := []string{
+			`DELETE FROM stat_bucket WHERE slot_id = $1`,
+			`DELETE FROM stat WHERE slot_id = $1`,
+			`DELETE FROM slot WHERE id = $1`,
+		}
+
+		for _, query := range queries {
+			_, err = tx.Exec(ctx, query, slotID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+func (s *Storage) CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	ctx, done := s.observe(ctx, "CreateSocialGroup")
+	defer done()
+
+	query := `
+		INSERT INTO social_group(description)
+		VALUES ($1)
+		RETURNING id
+	`
+
+	err := s.client.QueryRow(ctx, query, socialGroup.Description).Scan(&socialGroup.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) FindSocialGroups(ctx context.Context) ([]*model.Group, error) {
+	ctx, done := s.observe(ctx, "FindSocialGroups")
+	defer done()
+
+	query := `
+		SELECT id, description
+		FROM social_group
+		ORDER BY id
+	`
+
+	var socialGroups []*model.Group
+
+	err := pgxscan.Select(ctx, s.client, &socialGroups, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return socialGroups, nil
+}
+
+func (s *Storage) UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error {
+	ctx, done := s.observe(ctx, "UpdateSocialGroup")
+	defer done()
+
+	query := `
+		UPDATE social_group
+		SET description = $2
+		WHERE id = $1
+	`
+
+	_, err := s.client.Exec(ctx, query, socialGroup.ID, socialGroup.Description)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSocialGroup deletes the social group together with its statistics.
+func (s *Storage) DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error {
+	ctx, done := s.observe(ctx, "DeleteSocialGroup")
+	defer done()
+
+	return s.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		// FOR UPDATE waits for the transactions that count stats of the social group, their foreign keys
+		// hold it FOR KEY SHARE, and keeps new ones out until the group is gone
+		_, err := tx.Exec(ctx, `SELECT id FROM social_group WHERE id = $1 FOR UPDATE`, socialGroupID)
+		if err != nil {
+			return err
+		}
+
+		queries := []string{
+			`DELETE FROM stat_bucket WHERE social_group_id = $1`,
+			`DELETE FROM stat WHERE social_group_id = $1`,
+			`DELETE FROM social_group WHERE id = $1`,
+		}
+
+		for _, query := range queries {
+			_, err = tx.Exec(ctx, query, socialGroupID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
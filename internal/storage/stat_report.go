@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+	"strings"
+)
+
+var dimensionColumns = map[string]string{
+	model.DimensionBanner: "banner_id",
+	model.DimensionSlot:   "slot_id",
+	model.DimensionGroup:  "social_group_id",
+}
+
+func (s *Storage) FindStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error) {
+	ctx, done := s.observe(ctx, "FindStatReports")
+	defer done()
+
+	var (
+		columns    []string
+		conditions []string
+		args       []interface{}
+	)
+
+	for _, dimension := range filter.GroupBy {
+		column, ok := dimensionColumns[dimension]
+		if !ok {
+			return nil, fmt.Errorf("unknown dimension %q", dimension)
+		}
+		columns = append(columns, column)
+	}
+
+	for _, condition := range []struct {
+		column string
+		id     *uuid.UUID
+	}{
+		{column: "banner_id", id: filter.BannerID},
+		{column: "slot_id", id: filter.SlotID},
+		{column: "social_group_id", id: filter.GroupID},
+	} {
+		if condition.id == nil {
+			continue
+		}
+		args = append(args, condition.id)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", condition.column, len(args)))
+	}
+
+	query := "SELECT "
+	for _, column := range columns {
+		query += column + ", "
+	}
+	query += "COALESCE(SUM(shows), 0) AS shows, COALESCE(SUM(clicks), 0) AS clicks, "
+	query += "COALESCE(SUM(conversions), 0) AS conversions, COALESCE(SUM(revenue), 0) AS revenue FROM stat"
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if len(columns) != 0 {
+		query += " GROUP BY " + strings.Join(columns, ", ")
+		query += " ORDER BY " + strings.Join(columns, ", ")
+	}
+
+	var reports []*model.StatReport
+
+	err := pgxscan.Select(ctx, s.client, &reports, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,611 @@
+// Package storagetest is the conformance test suite every storage backend has to pass.
+package storagetest
+
+import (
+	"context"
+	"github.com/aakosarev/banner-rotation/internal/errors"
+	"github.com/aakosarev/banner-rotation/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type Storage interface {
+	Ping(ctx context.Context) error
+	AddBannerToSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
+	FindBannerSlot(ctx context.Context, bannerID, slotID *uuid.UUID) (*model.BannerSlot, error)
+	RemoveBannerFromSlot(ctx context.Context, bannerID, slotID *uuid.UUID) error
+	AddBannersToSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error)
+	RemoveBannersFromSlots(ctx context.Context, bannerSlots []*model.BannerSlot) ([]error, error)
+	FindStatByParams(ctx context.Context, bannerID, slotID, socialGroupID *uuid.UUID) (*model.Stat, error)
+	AddClickToStat(ctx context.Context, stat *model.Stat, event *model.Event) error
+	AddConversionToStat(ctx context.Context, stat *model.Stat, event *model.Event) error
+	AddShowToStat(ctx context.Context, stat *model.Stat, event *model.Event) error
+	AddStatDeltas(ctx context.Context, deltas []*model.StatDelta, events []*model.Event) error
+	FindStatsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID) ([]*model.Stat, error)
+	FindStatBucketsBySlotAndSocialGroup(ctx context.Context, slotID, socialGroupID *uuid.UUID, since time.Time) ([]*model.StatBucket, error)
+	FindBannersInSlot(ctx context.Context, slotID *uuid.UUID) ([]*uuid.UUID, error)
+	FindBannerByID(ctx context.Context, bannerID *uuid.UUID) (*model.Banner, error)
+	FindSlotByID(ctx context.Context, slotID *uuid.UUID) (*model.Slot, error)
+	FindSocialGroupByID(ctx context.Context, socialGroupID *uuid.UUID) (*model.Group, error)
+	FindSlotsOfBanner(ctx context.Context, bannerID *uuid.UUID) ([]*uuid.UUID, error)
+	FindStatReports(ctx context.Context, filter *model.StatFilter) ([]*model.StatReport, error)
+	RelayEvents(ctx context.Context, limit int, publish func(events []*model.Event) error) (int, error)
+	DeleteEventsSentBefore(ctx context.Context, before time.Time) (int, error)
+	FindImpressionByID(ctx context.Context, impressionID *uuid.UUID) (*model.Impression, error)
+	DeleteImpressionsBefore(ctx context.Context, before time.Time) (int, error)
+
+	CreateBanner(ctx context.Context, banner *model.Banner) error
+	FindBanners(ctx context.Context) ([]*model.Banner, error)
+	UpdateBanner(ctx context.Context, banner *model.Banner) error
+	DeleteBanner(ctx context.Context, bannerID *uuid.UUID) error
+
+	CreateSlot(ctx context.Context, slot *model.Slot) error
+	FindSlots(ctx context.Context) ([]*model.Slot, error)
+	UpdateSlot(ctx context.Context, slot *model.Slot) error
+	DeleteSlot(ctx context.Context, slotID *uuid.UUID) error
+
+	CreateSocialGroup(ctx context.Context, socialGroup *model.Group) error
+	FindSocialGroups(ctx context.Context) ([]*model.Group, error)
+	UpdateSocialGroup(ctx context.Context, socialGroup *model.Group) error
+	DeleteSocialGroup(ctx context.Context, socialGroupID *uuid.UUID) error
+}
+
+// Run runs the suite against the storages returned by newStorage. The storages may be shared
+// and already have data, so the tests only look at the rows they create.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	t.Run("ping", func(t *testing.T) {
+		require.NoError(t, newStorage(t).Ping(context.Background()))
+	})
+	t.Run("not found", func(t *testing.T) {
+		testNotFound(t, newStorage(t))
+	})
+	t.Run("entities", func(t *testing.T) {
+		testEntities(t, newStorage(t))
+	})
+	t.Run("links", func(t *testing.T) {
+		testLinks(t, newStorage(t))
+	})
+	t.Run("bulk links", func(t *testing.T) {
+		testBulkLinks(t, newStorage(t))
+	})
+	t.Run("stats", func(t *testing.T) {
+		testStats(t, newStorage(t))
+	})
+	t.Run("conversions", func(t *testing.T) {
+		testConversions(t, newStorage(t))
+	})
+	t.Run("impressions", func(t *testing.T) {
+		testImpressions(t, newStorage(t))
+	})
+	t.Run("impression rewards", func(t *testing.T) {
+		testImpressionRewards(t, newStorage(t))
+	})
+	t.Run("stat reports", func(t *testing.T) {
+		testStatReports(t, newStorage(t))
+	})
+	t.Run("outbox", func(t *testing.T) {
+		testOutbox(t, newStorage(t))
+	})
+}
+
+type fixture struct {
+	banners     []*model.Banner
+	slot        *model.Slot
+	socialGroup *model.Group
+}
+
+func newFixture(t *testing.T, s Storage, banners int) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &fixture{
+		slot:        &model.Slot{Description: "storage test slot"},
+		socialGroup: &model.Group{Description: "storage test social group"},
+	}
+	require.NoError(t, s.CreateSlot(ctx, f.slot))
+	require.NoError(t, s.CreateSocialGroup(ctx, f.socialGroup))
+
+	for i := 0; i < banners; i++ {
+		banner := &model.Banner{Description: "storage test banner"}
+		require.NoError(t, s.CreateBanner(ctx, banner))
+		f.banners = append(f.banners, banner)
+	}
+
+	t.Cleanup(func() {
+		for _, banner := range f.banners {
+			s.RemoveBannerFromSlot(ctx, &banner.ID, &f.slot.ID)
+			s.DeleteBanner(ctx, &banner.ID)
+		}
+		s.DeleteSlot(ctx, &f.slot.ID)
+		s.DeleteSocialGroup(ctx, &f.socialGroup.ID)
+	})
+
+	return f
+}
+
+func (f *fixture) stat(banner int) *model.Stat {
+	return &model.Stat{
+		BannerID: f.banners[banner].ID,
+		SlotID:   f.slot.ID,
+		GroupID:  f.socialGroup.ID,
+	}
+}
+
+func testNotFound(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	banner, err := s.FindBannerByID(ctx, &id)
+	require.NoError(t, err)
+	require.Nil(t, banner)
+
+	slot, err := s.FindSlotByID(ctx, &id)
+	require.NoError(t, err)
+	require.Nil(t, slot)
+
+	socialGroup, err := s.FindSocialGroupByID(ctx, &id)
+	require.NoError(t, err)
+	require.Nil(t, socialGroup)
+
+	bannerSlot, err := s.FindBannerSlot(ctx, &id, &id)
+	require.NoError(t, err)
+	require.Nil(t, bannerSlot)
+
+	stat, err := s.FindStatByParams(ctx, &id, &id, &id)
+	require.NoError(t, err)
+	require.Nil(t, stat)
+
+	stats, err := s.FindStatsBySlotAndSocialGroup(ctx, &id, &id)
+	require.NoError(t, err)
+	require.Empty(t, stats)
+
+	bannerIDs, err := s.FindBannersInSlot(ctx, &id)
+	require.NoError(t, err)
+	require.Empty(t, bannerIDs)
+}
+
+func testEntities(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 1)
+
+	require.NotEqual(t, uuid.Nil, f.banners[0].ID)
+
+	banner, err := s.FindBannerByID(ctx, &f.banners[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, f.banners[0], banner)
+
+	banners, err := s.FindBanners(ctx)
+	require.NoError(t, err)
+	require.Contains(t, banners, f.banners[0])
+
+	slots, err := s.FindSlots(ctx)
+	require.NoError(t, err)
+	require.Contains(t, slots, f.slot)
+
+	socialGroups, err := s.FindSocialGroups(ctx)
+	require.NoError(t, err)
+	require.Contains(t, socialGroups, f.socialGroup)
+
+	f.banners[0].Description = "updated banner"
+	require.NoError(t, s.UpdateBanner(ctx, f.banners[0]))
+	banner, err = s.FindBannerByID(ctx, &f.banners[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated banner", banner.Description)
+
+	f.slot.Description = "updated slot"
+	require.NoError(t, s.UpdateSlot(ctx, f.slot))
+	slot, err := s.FindSlotByID(ctx, &f.slot.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated slot", slot.Description)
+
+	f.socialGroup.Description = "updated social group"
+	require.NoError(t, s.UpdateSocialGroup(ctx, f.socialGroup))
+	socialGroup, err := s.FindSocialGroupByID(ctx, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated social group", socialGroup.Description)
+
+	// updating a missing row changes nothing
+	missing := &model.Banner{ID: uuid.New(), Description: "missing banner"}
+	require.NoError(t, s.UpdateBanner(ctx, missing))
+	banner, err = s.FindBannerByID(ctx, &missing.ID)
+	require.NoError(t, err)
+	require.Nil(t, banner)
+
+	require.NoError(t, s.DeleteBanner(ctx, &f.banners[0].ID))
+	banner, err = s.FindBannerByID(ctx, &f.banners[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, banner)
+}
+
+func testLinks(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 2)
+
+	require.NoError(t, s.AddBannerToSlot(ctx, &f.banners[0].ID, &f.slot.ID))
+	require.NoError(t, s.AddBannerToSlot(ctx, &f.banners[1].ID, &f.slot.ID))
+	require.ErrorIs(t, s.AddBannerToSlot(ctx, &f.banners[0].ID, &f.slot.ID), errors.ErrBannerAlreadyLinkedToSlot)
+	require.ErrorIs(t, s.DeleteBanner(ctx, &f.banners[0].ID), errors.ErrBannerLinkedToSlot)
+	require.ErrorIs(t, s.DeleteSlot(ctx, &f.slot.ID), errors.ErrSlotHasBanners)
+
+	bannerSlot, err := s.FindBannerSlot(ctx, &f.banners[0].ID, &f.slot.ID)
+	require.NoError(t, err)
+	require.Equal(t, &model.BannerSlot{BannerID: f.banners[0].ID, SlotID: f.slot.ID}, bannerSlot)
+
+	bannerIDs, err := s.FindBannersInSlot(ctx, &f.slot.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*uuid.UUID{&f.banners[0].ID, &f.banners[1].ID}, bannerIDs)
+
+	slotIDs, err := s.FindSlotsOfBanner(ctx, &f.banners[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, []*uuid.UUID{&f.slot.ID}, slotIDs)
+
+	require.NoError(t, s.RemoveBannerFromSlot(ctx, &f.banners[0].ID, &f.slot.ID))
+
+	bannerSlot, err = s.FindBannerSlot(ctx, &f.banners[0].ID, &f.slot.ID)
+	require.NoError(t, err)
+	require.Nil(t, bannerSlot)
+
+	bannerIDs, err = s.FindBannersInSlot(ctx, &f.slot.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*uuid.UUID{&f.banners[1].ID}, bannerIDs)
+}
+
+func testBulkLinks(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 2)
+	missing := uuid.New()
+
+	require.NoError(t, s.AddBannerToSlot(ctx, &f.banners[1].ID, &f.slot.ID))
+
+	results, err := s.AddBannersToSlots(ctx, []*model.BannerSlot{
+		{BannerID: f.banners[0].ID, SlotID: f.slot.ID},
+		{BannerID: f.banners[1].ID, SlotID: f.slot.ID},
+		{BannerID: missing, SlotID: f.slot.ID},
+		{BannerID: f.banners[0].ID, SlotID: missing},
+		{BannerID: f.banners[0].ID, SlotID: f.slot.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	require.NoError(t, results[0])
+	require.ErrorIs(t, results[1], errors.ErrBannerAlreadyLinkedToSlot)
+	require.ErrorIs(t, results[2], errors.ErrBannerNotFound)
+	require.ErrorIs(t, results[3], errors.ErrSlotNotFound)
+	require.ErrorIs(t, results[4], errors.ErrBannerAlreadyLinkedToSlot, "a pair repeated in one request is linked once")
+
+	bannerIDs, err := s.FindBannersInSlot(ctx, &f.slot.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*uuid.UUID{&f.banners[0].ID, &f.banners[1].ID}, bannerIDs)
+
+	results, err = s.RemoveBannersFromSlots(ctx, []*model.BannerSlot{
+		{BannerID: f.banners[0].ID, SlotID: f.slot.ID},
+		{BannerID: f.banners[0].ID, SlotID: f.slot.ID},
+		{BannerID: missing, SlotID: f.slot.ID},
+	})
+	require.NoError(t, err)
+	require.NoError(t, results[0])
+	require.ErrorIs(t, results[1], errors.ErrBannerNotLinkedToSlot)
+	require.ErrorIs(t, results[2], errors.ErrBannerNotFound)
+
+	bannerIDs, err = s.FindBannersInSlot(ctx, &f.slot.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*uuid.UUID{&f.banners[1].ID}, bannerIDs)
+
+	results, err = s.AddBannersToSlots(ctx, []*model.BannerSlot{{BannerID: missing, SlotID: missing}})
+	require.NoError(t, err)
+	require.ErrorIs(t, results[0], errors.ErrBannerNotFound)
+}
+
+func testStats(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 2)
+
+	require.NoError(t, s.AddShowToStat(ctx, f.stat(0), model.NewEvent(model.EventShow, f.stat(0))))
+	require.NoError(t, s.AddShowToStat(ctx, f.stat(0), model.NewEvent(model.EventShow, f.stat(0))))
+	require.NoError(t, s.AddClickToStat(ctx, f.stat(0), model.NewEvent(model.EventClick, f.stat(0))))
+	// the first event of a banner may be a click
+	require.NoError(t, s.AddClickToStat(ctx, f.stat(1), model.NewEvent(model.EventClick, f.stat(1))))
+
+	now := time.Now()
+	require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{{
+		BannerID: f.banners[1].ID,
+		SlotID:   f.slot.ID,
+		GroupID:  f.socialGroup.ID,
+		Bucket:   now.Truncate(time.Hour),
+		Shows:    10,
+		Clicks:   2,
+	}}, nil))
+
+	stat, err := s.FindStatByParams(ctx, &f.banners[0].ID, &f.slot.ID, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, &model.Stat{BannerID: f.banners[0].ID, SlotID: f.slot.ID, GroupID: f.socialGroup.ID, Shows: 2, Clicks: 1}, stat)
+
+	stats, err := s.FindStatsBySlotAndSocialGroup(ctx, &f.slot.ID, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*model.Stat{
+		{BannerID: f.banners[0].ID, SlotID: f.slot.ID, GroupID: f.socialGroup.ID, Shows: 2, Clicks: 1},
+		{BannerID: f.banners[1].ID, SlotID: f.slot.ID, GroupID: f.socialGroup.ID, Shows: 10, Clicks: 3},
+	}, stats)
+
+	buckets, err := s.FindStatBucketsBySlotAndSocialGroup(ctx, &f.slot.ID, &f.socialGroup.ID, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	var shows, clicks int
+	for _, bucket := range buckets {
+		require.False(t, bucket.Bucket.Before(now.Add(-time.Hour)))
+		shows += bucket.Shows
+		clicks += bucket.Clicks
+	}
+	require.Equal(t, 12, shows)
+	require.Equal(t, 4, clicks)
+
+	buckets, err = s.FindStatBucketsBySlotAndSocialGroup(ctx, &f.slot.ID, &f.socialGroup.ID, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, buckets)
+
+	require.NoError(t, s.DeleteBanner(ctx, &f.banners[0].ID))
+
+	stat, err = s.FindStatByParams(ctx, &f.banners[0].ID, &f.slot.ID, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.Nil(t, stat, "statistics are deleted with the banner")
+
+	require.NoError(t, s.DeleteSocialGroup(ctx, &f.socialGroup.ID))
+
+	stats, err = s.FindStatsBySlotAndSocialGroup(ctx, &f.slot.ID, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.Empty(t, stats, "statistics are deleted with the social group")
+}
+
+func testImpressions(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 2)
+
+	missing := uuid.New()
+	impression, err := s.FindImpressionByID(ctx, &missing)
+	require.NoError(t, err)
+	require.Nil(t, impression)
+
+	old := model.NewEvent(model.EventShow, f.stat(0))
+	old.Timestamp = old.Timestamp.Add(-48 * time.Hour)
+	require.NoError(t, s.AddShowToStat(ctx, f.stat(0), old))
+
+	cached := model.NewEvent(model.EventShow, f.stat(1))
+	cached.Features = model.Features{"country": "de"}
+	click := model.NewEvent(model.EventClick, f.stat(1))
+	require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{{
+		BannerID: f.banners[1].ID,
+		SlotID:   f.slot.ID,
+		GroupID:  f.socialGroup.ID,
+		Bucket:   time.Now().Truncate(time.Hour),
+		Shows:    1,
+		Clicks:   1,
+	}}, []*model.Event{cached, click}))
+
+	for _, event := range []*model.Event{old, cached} {
+		impression, err = s.FindImpressionByID(ctx, &event.ID)
+		require.NoError(t, err)
+		require.NotNil(t, impression)
+		require.Equal(t, event.ID, impression.ID)
+		require.Equal(t, event.BannerID, impression.BannerID)
+		require.Equal(t, f.slot.ID, impression.SlotID)
+		require.Equal(t, f.socialGroup.ID, impression.GroupID)
+		require.WithinDuration(t, event.Timestamp, impression.ShownAt, time.Millisecond)
+		require.Equal(t, event.Features, impression.Features)
+	}
+
+	impression, err = s.FindImpressionByID(ctx, &click.ID)
+	require.NoError(t, err)
+	require.Nil(t, impression, "only shows are impressions")
+
+	deleted, err := s.DeleteImpressionsBefore(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, 1)
+
+	impression, err = s.FindImpressionByID(ctx, &old.ID)
+	require.NoError(t, err)
+	require.Nil(t, impression)
+
+	impression, err = s.FindImpressionByID(ctx, &cached.ID)
+	require.NoError(t, err)
+	require.NotNil(t, impression)
+}
+
+func testImpressionRewards(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 2)
+
+	show := model.NewEvent(model.EventShow, f.stat(0))
+	require.NoError(t, s.AddShowToStat(ctx, f.stat(0), show))
+
+	rewardEvent := func(eventType string, impressionID uuid.UUID) *model.Event {
+		event := model.NewEvent(eventType, f.stat(0))
+		event.ImpressionID = &impressionID
+		event.Value = 10
+		return event
+	}
+
+	require.NoError(t, s.AddClickToStat(ctx, f.stat(0), rewardEvent(model.EventClick, show.ID)))
+	err := s.AddClickToStat(ctx, f.stat(0), rewardEvent(model.EventClick, show.ID))
+	require.ErrorIs(t, err, errors.ErrImpressionAlreadyRewarded)
+
+	require.NoError(t, s.AddConversionToStat(ctx, f.stat(0), rewardEvent(model.EventConversion, show.ID)))
+	err = s.AddConversionToStat(ctx, f.stat(0), rewardEvent(model.EventConversion, show.ID))
+	require.ErrorIs(t, err, errors.ErrImpressionAlreadyRewarded)
+
+	err = s.AddClickToStat(ctx, f.stat(0), rewardEvent(model.EventClick, uuid.New()))
+	require.ErrorIs(t, err, errors.ErrImpressionNotFound)
+
+	stat, err := s.FindStatByParams(ctx, &f.banners[0].ID, &f.slot.ID, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, stat.Clicks, "the replayed click is not counted")
+	require.Equal(t, 1, stat.Conversions, "the replayed conversion is not counted")
+
+	impression, err := s.FindImpressionByID(ctx, &show.ID)
+	require.NoError(t, err)
+	require.True(t, impression.Rewarded(model.EventClick))
+	require.True(t, impression.Rewarded(model.EventConversion))
+
+	// flushed rewards mark their impressions too, and do not make up the pruned ones
+	cached := model.NewEvent(model.EventShow, f.stat(1))
+	click := rewardEvent(model.EventClick, cached.ID)
+	pruned := uuid.New()
+	require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{{
+		BannerID: f.banners[1].ID,
+		SlotID:   f.slot.ID,
+		GroupID:  f.socialGroup.ID,
+		Bucket:   time.Now().Truncate(time.Hour),
+		Shows:    1,
+		Clicks:   1,
+	}}, []*model.Event{cached, click, rewardEvent(model.EventClick, pruned)}))
+
+	impression, err = s.FindImpressionByID(ctx, &cached.ID)
+	require.NoError(t, err)
+	require.True(t, impression.Rewarded(model.EventClick))
+	require.False(t, impression.Rewarded(model.EventConversion))
+
+	impression, err = s.FindImpressionByID(ctx, &pruned)
+	require.NoError(t, err)
+	require.Nil(t, impression)
+}
+
+func testConversions(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 1)
+
+	require.NoError(t, s.AddShowToStat(ctx, f.stat(0), model.NewEvent(model.EventShow, f.stat(0))))
+
+	for _, value := range []float64{12.5, 0} {
+		event := model.NewEvent(model.EventConversion, f.stat(0))
+		event.Value = value
+		require.NoError(t, s.AddConversionToStat(ctx, f.stat(0), event))
+	}
+
+	require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{{
+		BannerID:    f.banners[0].ID,
+		SlotID:      f.slot.ID,
+		GroupID:     f.socialGroup.ID,
+		Bucket:      time.Now().Truncate(time.Hour),
+		Conversions: 1,
+		Revenue:     2.5,
+	}}, nil))
+
+	stat, err := s.FindStatByParams(ctx, &f.banners[0].ID, &f.slot.ID, &f.socialGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, &model.Stat{
+		BannerID: f.banners[0].ID, SlotID: f.slot.ID, GroupID: f.socialGroup.ID, Shows: 1, Conversions: 3, Revenue: 15,
+	}, stat)
+
+	buckets, err := s.FindStatBucketsBySlotAndSocialGroup(ctx, &f.slot.ID, &f.socialGroup.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	var (
+		conversions int
+		revenue     float64
+	)
+	for _, bucket := range buckets {
+		conversions += bucket.Conversions
+		revenue += bucket.Revenue
+	}
+	require.Equal(t, 3, conversions)
+	require.Equal(t, 15.0, revenue)
+
+	reports, err := s.FindStatReports(ctx, &model.StatFilter{SlotID: &f.slot.ID})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, int64(3), reports[0].Conversions)
+	require.Equal(t, 15.0, reports[0].Revenue)
+}
+
+func testStatReports(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 2)
+
+	for i, counts := range [][2]int{{30, 3}, {10, 2}} {
+		require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{{
+			BannerID: f.banners[i].ID,
+			SlotID:   f.slot.ID,
+			GroupID:  f.socialGroup.ID,
+			Bucket:   time.Now().Truncate(time.Hour),
+			Shows:    counts[0],
+			Clicks:   counts[1],
+		}}, nil))
+	}
+
+	reports, err := s.FindStatReports(ctx, &model.StatFilter{SlotID: &f.slot.ID})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, int64(40), reports[0].Shows)
+	require.Equal(t, int64(5), reports[0].Clicks)
+	require.Nil(t, reports[0].BannerID)
+
+	reports, err = s.FindStatReports(ctx, &model.StatFilter{
+		SlotID:  &f.slot.ID,
+		GroupBy: []string{model.DimensionBanner, model.DimensionSlot},
+	})
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	for _, report := range reports {
+		require.Equal(t, f.slot.ID, *report.SlotID)
+		require.Nil(t, report.GroupID)
+	}
+	require.Less(t, reports[0].BannerID.String(), reports[1].BannerID.String())
+
+	reports, err = s.FindStatReports(ctx, &model.StatFilter{BannerID: &f.banners[1].ID, GroupBy: []string{model.DimensionGroup}})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, f.socialGroup.ID, *reports[0].GroupID)
+	require.Equal(t, int64(10), reports[0].Shows)
+
+	_, err = s.FindStatReports(ctx, &model.StatFilter{GroupBy: []string{"country"}})
+	require.Error(t, err)
+}
+
+func testOutbox(t *testing.T, s Storage) {
+	ctx := context.Background()
+	f := newFixture(t, s, 1)
+
+	show := model.NewEvent(model.EventShow, f.stat(0))
+	click := model.NewEvent(model.EventClick, f.stat(0))
+	require.NoError(t, s.AddShowToStat(ctx, f.stat(0), show))
+	require.NoError(t, s.AddStatDeltas(ctx, []*model.StatDelta{{
+		BannerID: f.banners[0].ID,
+		SlotID:   f.slot.ID,
+		GroupID:  f.socialGroup.ID,
+		Bucket:   click.Timestamp.Truncate(time.Hour),
+		Clicks:   1,
+	}}, []*model.Event{click}))
+
+	_, err := s.RelayEvents(ctx, 10, func([]*model.Event) error {
+		return context.Canceled
+	})
+	require.Error(t, err, "publish errors are returned")
+
+	relayed := make(map[uuid.UUID]*model.Event)
+	for {
+		n, err := s.RelayEvents(ctx, 10, func(events []*model.Event) error {
+			for _, event := range events {
+				relayed[event.ID] = event
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	// events kept in the outbox until they are published once
+	require.Contains(t, relayed, show.ID)
+	require.Contains(t, relayed, click.ID)
+	require.Equal(t, model.EventClick, relayed[click.ID].Type)
+	require.Equal(t, click.BannerID, relayed[click.ID].BannerID)
+	require.WithinDuration(t, click.Timestamp, relayed[click.ID].Timestamp, time.Millisecond)
+
+	_, err = s.DeleteEventsSentBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	n, err := s.RelayEvents(ctx, 10, func([]*model.Event) error { return nil })
+	require.NoError(t, err)
+	require.Zero(t, n, "pruning does not bring sent events back")
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stat_bucket (
+    banner_id       UUID,
+    slot_id         UUID,
+    social_group_id UUID,
+    bucket          TIMESTAMPTZ,
+    shows           INT,
+    clicks          INT,
+    PRIMARY KEY (slot_id, social_group_id, bucket, banner_id),
+    FOREIGN KEY (banner_id) REFERENCES banner (id),
+    FOREIGN KEY (slot_id) REFERENCES slot (id),
+    FOREIGN KEY (social_group_id) REFERENCES social_group (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stat_bucket;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE stat
+    ADD COLUMN IF NOT EXISTS conversions INT              NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS revenue     DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE stat_bucket
+    ADD COLUMN IF NOT EXISTS conversions INT              NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS revenue     DOUBLE PRECISION NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE stat_bucket
+    DROP COLUMN IF EXISTS conversions,
+    DROP COLUMN IF EXISTS revenue;
+
+ALTER TABLE stat
+    DROP COLUMN IF EXISTS conversions,
+    DROP COLUMN IF EXISTS revenue;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS impression (
+    id              UUID PRIMARY KEY,
+    banner_id       UUID        NOT NULL,
+    slot_id         UUID        NOT NULL,
+    social_group_id UUID        NOT NULL,
+    shown_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS impression_shown_at_idx ON impression (shown_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS impression;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE impression ADD COLUMN IF NOT EXISTS features JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE impression DROP COLUMN IF EXISTS features;
+-- +goose StatementEnd
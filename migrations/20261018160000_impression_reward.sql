@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE impression ADD COLUMN IF NOT EXISTS clicked_at TIMESTAMPTZ;
+ALTER TABLE impression ADD COLUMN IF NOT EXISTS converted_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE impression DROP COLUMN IF EXISTS converted_at;
+ALTER TABLE impression DROP COLUMN IF EXISTS clicked_at;
+-- +goose StatementEnd
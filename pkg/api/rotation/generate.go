@@ -0,0 +1,4 @@
+// Package rotation is the generated gRPC API of the banner rotation service.
+package rotation
+
+//go:generate protoc -I ../../../api --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative rotation.proto
@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: rotation.proto
+
+package rotation
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	RotationService_AddBannerToSlot_FullMethodName      = "/rotation.v1.RotationService/AddBannerToSlot"
+	RotationService_RemoveBannerFromSlot_FullMethodName = "/rotation.v1.RotationService/RemoveBannerFromSlot"
+	RotationService_SelectBanner_FullMethodName         = "/rotation.v1.RotationService/SelectBanner"
+	RotationService_AddClick_FullMethodName             = "/rotation.v1.RotationService/AddClick"
+	RotationService_AddConversion_FullMethodName        = "/rotation.v1.RotationService/AddConversion"
+)
+
+// RotationServiceClient is the client API for RotationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RotationServiceClient interface {
+	AddBannerToSlot(ctx context.Context, in *AddBannerToSlotRequest, opts ...grpc.CallOption) (*AddBannerToSlotResponse, error)
+	RemoveBannerFromSlot(ctx context.Context, in *RemoveBannerFromSlotRequest, opts ...grpc.CallOption) (*RemoveBannerFromSlotResponse, error)
+	// SelectBanner picks the banner to show in the slot and counts the show.
+	SelectBanner(ctx context.Context, in *SelectBannerRequest, opts ...grpc.CallOption) (*SelectBannerResponse, error)
+	AddClick(ctx context.Context, in *AddClickRequest, opts ...grpc.CallOption) (*AddClickResponse, error)
+	// AddConversion attributes a conversion, worth value, to the banner shown in the slot.
+	AddConversion(ctx context.Context, in *AddConversionRequest, opts ...grpc.CallOption) (*AddConversionResponse, error)
+}
+
+type rotationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRotationServiceClient(cc grpc.ClientConnInterface) RotationServiceClient {
+	return &rotationServiceClient{cc}
+}
+
+func (c *rotationServiceClient) AddBannerToSlot(ctx context.Context, in *AddBannerToSlotRequest, opts ...grpc.CallOption) (*AddBannerToSlotResponse, error) {
+	out := new(AddBannerToSlotResponse)
+	err := c.cc.Invoke(ctx, RotationService_AddBannerToSlot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rotationServiceClient) RemoveBannerFromSlot(ctx context.Context, in *RemoveBannerFromSlotRequest, opts ...grpc.CallOption) (*RemoveBannerFromSlotResponse, error) {
+	out := new(RemoveBannerFromSlotResponse)
+	err := c.cc.Invoke(ctx, RotationService_RemoveBannerFromSlot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rotationServiceClient) SelectBanner(ctx context.Context, in *SelectBannerRequest, opts ...grpc.CallOption) (*SelectBannerResponse, error) {
+	out := new(SelectBannerResponse)
+	err := c.cc.Invoke(ctx, RotationService_SelectBanner_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rotationServiceClient) AddClick(ctx context.Context, in *AddClickRequest, opts ...grpc.CallOption) (*AddClickResponse, error) {
+	out := new(AddClickResponse)
+	err := c.cc.Invoke(ctx, RotationService_AddClick_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rotationServiceClient) AddConversion(ctx context.Context, in *AddConversionRequest, opts ...grpc.CallOption) (*AddConversionResponse, error) {
+	out := new(AddConversionResponse)
+	err := c.cc.Invoke(ctx, RotationService_AddConversion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RotationServiceServer is the server API for RotationService service.
+// All implementations must embed UnimplementedRotationServiceServer
+// for forward compatibility
+type RotationServiceServer interface {
+	AddBannerToSlot(context.Context, *AddBannerToSlotRequest) (*AddBannerToSlotResponse, error)
+	RemoveBannerFromSlot(context.Context, *RemoveBannerFromSlotRequest) (*RemoveBannerFromSlotResponse, error)
+	// SelectBanner picks the banner to show in the slot and counts the show.
+	SelectBanner(context.Context, *SelectBannerRequest) (*SelectBannerResponse, error)
+	AddClick(context.Context, *AddClickRequest) (*AddClickResponse, error)
+	// AddConversion attributes a conversion, worth value, to the banner shown in the slot.
+	AddConversion(context.Context, *AddConversionRequest) (*AddConversionResponse, error)
+	mustEmbedUnimplementedRotationServiceServer()
+}
+
+// UnimplementedRotationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRotationServiceServer struct {
+}
+
+func (UnimplementedRotationServiceServer) AddBannerToSlot(context.Context, *AddBannerToSlotRequest) (*AddBannerToSlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBannerToSlot not implemented")
+}
+func (UnimplementedRotationServiceServer) RemoveBannerFromSlot(context.Context, *RemoveBannerFromSlotRequest) (*RemoveBannerFromSlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBannerFromSlot not implemented")
+}
+func (UnimplementedRotationServiceServer) SelectBanner(context.Context, *SelectBannerRequest) (*SelectBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectBanner not implemented")
+}
+func (UnimplementedRotationServiceServer) AddClick(context.Context, *AddClickRequest) (*AddClickResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddClick not implemented")
+}
+func (UnimplementedRotationServiceServer) AddConversion(context.Context, *AddConversionRequest) (*AddConversionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddConversion not implemented")
+}
+func (UnimplementedRotationServiceServer) mustEmbedUnimplementedRotationServiceServer() {}
+
+// UnsafeRotationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RotationServiceServer will
+// result in compilation errors.
+type UnsafeRotationServiceServer interface {
+	mustEmbedUnimplementedRotationServiceServer()
+}
+
+func RegisterRotationServiceServer(s grpc.ServiceRegistrar, srv RotationServiceServer) {
+	s.RegisterService(&RotationService_ServiceDesc, srv)
+}
+
+func _RotationService_AddBannerToSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBannerToSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RotationServiceServer).AddBannerToSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RotationService_AddBannerToSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RotationServiceServer).AddBannerToSlot(ctx, req.(*AddBannerToSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RotationService_RemoveBannerFromSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBannerFromSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RotationServiceServer).RemoveBannerFromSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RotationService_RemoveBannerFromSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RotationServiceServer).RemoveBannerFromSlot(ctx, req.(*RemoveBannerFromSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RotationService_SelectBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RotationServiceServer).SelectBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RotationService_SelectBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RotationServiceServer).SelectBanner(ctx, req.(*SelectBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RotationService_AddClick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddClickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RotationServiceServer).AddClick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RotationService_AddClick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RotationServiceServer).AddClick(ctx, req.(*AddClickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RotationService_AddConversion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddConversionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RotationServiceServer).AddConversion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RotationService_AddConversion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RotationServiceServer).AddConversion(ctx, req.(*AddConversionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RotationService_ServiceDesc is the grpc.ServiceDesc for RotationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RotationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rotation.v1.RotationService",
+	HandlerType: (*RotationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddBannerToSlot",
+			Handler:    _RotationService_AddBannerToSlot_Handler,
+		},
+		{
+			MethodName: "RemoveBannerFromSlot",
+			Handler:    _RotationService_RemoveBannerFromSlot_Handler,
+		},
+		{
+			MethodName: "SelectBanner",
+			Handler:    _RotationService_SelectBanner_Handler,
+		},
+		{
+			MethodName: "AddClick",
+			Handler:    _RotationService_AddClick_Handler,
+		},
+		{
+			MethodName: "AddConversion",
+			Handler:    _RotationService_AddConversion_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rotation.proto",
+}